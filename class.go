@@ -0,0 +1,87 @@
+package go2ts
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConvertMethodSet converts the exported methods of a named type to a
+// typescript class declaration. Method declarations may be configured by
+// Converter.ConfigureFunc, where FuncConf.IsMethod is always set and
+// FuncConf.MethodName defaults to the golang method name. Methods are output
+// once per name, so for a pointer type, methods with value and pointer
+// receivers are included without duplicates. Methods are output in golang
+// method order, or sorted by name if Converter.SortMethods is set. If
+// Converter.AbstractClasses is set an abstract class is output instead.
+func (c *Converter) ConvertMethodSet(t reflect.Type) string {
+	nt := t
+	for nt.Kind() == reflect.Ptr {
+		nt = nt.Elem()
+	}
+
+	var names []string
+	methods := make(map[string]string)
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		mt := m.Type
+		if t.Kind() == reflect.Interface {
+			mt = withReceiver(t, mt)
+		}
+		var fconf FuncConf
+		if c.ConfigureFunc != nil {
+			fconf = c.ConfigureFunc(mt)
+		}
+		fconf.IsMethod = true
+		if fconf.MethodName == "" {
+			fconf.MethodName = m.Name
+		}
+		if _, ok := methods[fconf.MethodName]; ok {
+			continue
+		}
+		decl := c.funcDeclaration(mt, fconf)
+		if c.AbstractClasses {
+			// overloads are output one per line
+			decl = "abstract " + strings.ReplaceAll(decl, "\n", "\nabstract ")
+		}
+		names = append(names, fconf.MethodName)
+		methods[fconf.MethodName] = decl
+	}
+	if c.SortMethods {
+		sort.Strings(names)
+	}
+
+	name := c.typeName(nt)
+	class := "declare class"
+	if c.AbstractClasses {
+		class = "abstract class"
+	}
+	if len(methods) == 0 {
+		return fmt.Sprintf("export %s %s {}", class, name)
+	}
+	indent := c.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	var decls []string
+	for _, n := range names {
+		decls = append(decls, methods[n])
+	}
+	body := strings.ReplaceAll(strings.Join(decls, "\n"), "\n", "\n"+indent)
+	return fmt.Sprintf("export %s %s {\n%s%s\n}", class, name, indent, body)
+}
+
+// withReceiver adds a receiver as the first parameter to an interface method
+// type, so that it has the same form as methods of concrete types.
+func withReceiver(recv reflect.Type, t reflect.Type) reflect.Type {
+	in := []reflect.Type{recv}
+	for i := 0; i < t.NumIn(); i++ {
+		in = append(in, t.In(i))
+	}
+	var out []reflect.Type
+	for i := 0; i < t.NumOut(); i++ {
+		out = append(out, t.Out(i))
+	}
+	return reflect.FuncOf(in, out, t.IsVariadic())
+}
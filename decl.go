@@ -0,0 +1,342 @@
+package go2ts
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// StructStyle determines how structs are declared by the converter.
+type StructStyle int
+
+const (
+	// StructStyleInterface declares structs as interfaces e.g.
+	// export interface User { Name: string }
+	StructStyleInterface StructStyle = iota
+	// StructStyleTypeAlias declares structs as type aliases e.g.
+	// export type User = { Name: string }
+	StructStyleTypeAlias
+)
+
+// Declare adds named types to be output as typescript declarations by
+// Converter.File. Declared types are referenced by name wherever they are
+// converted. Pointer types are declared as their element type.
+func (c *Converter) Declare(types ...reflect.Type) {
+	for _, t := range types {
+		c.declare(t)
+	}
+}
+
+// declare adds a named type to the declarations and returns its name.
+func (c *Converter) declare(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if c.declared[t] {
+		return c.types[t]
+	}
+	if t.Name() == "" {
+		panic(fmt.Errorf("cannot declare unnamed type: %v", t))
+	}
+	c.types[t] = c.typeName(t)
+	c.declared[t] = true
+	c.decls = append(c.decls, t)
+	return c.types[t]
+}
+
+// declareStruct declares the passed type if it is a named struct (or pointer
+// to a named struct) that is not already in the types table. Structs with a
+// custom JSON encoding are not declared since they are converted to unknown.
+func (c *Converter) declareStruct(t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if _, ok := c.types[t]; ok || t.Kind() != reflect.Struct || t.Name() == "" || t == timeType || isMarshaler(t) {
+		return
+	}
+	c.declare(t)
+}
+
+// hoistStructParam declares the passed type with the passed name if it is an
+// anonymous struct (or pointer to an anonymous struct) with at least
+// Converter.HoistStructParams fields. A number is appended to the name if it
+// is already used by another type e.g. Opts1.
+func (c *Converter) hoistStructParam(t reflect.Type, name string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if c.HoistStructParams <= 0 || t.Kind() != reflect.Struct || t.Name() != "" {
+		return
+	}
+	if _, ok := c.types[t]; ok || len(c.structFields(t)) < c.HoistStructParams {
+		return
+	}
+	unique := name
+	for n := 1; c.nameInUse(unique); n++ {
+		unique = fmt.Sprintf("%s%d", name, n)
+	}
+	c.types[t] = unique
+	c.declared[t] = true
+	c.decls = append(c.decls, t)
+}
+
+// nameInUse determines if a type in the types table has the passed name.
+func (c *Converter) nameInUse(name string) bool {
+	for _, n := range c.types {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// declareAlias declares the passed type if it is a named map, slice or array
+// and Converter.AliasNamedTypes is set, or a named primitive and
+// Converter.AliasNamedPrimitives is set.
+func (c *Converter) declareAlias(t reflect.Type) {
+	if t.Name() == "" {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		if c.AliasNamedTypes {
+			c.declare(t)
+		}
+	default:
+		if _, ok := primitiveKinds[t.Kind()]; ok && c.AliasNamedPrimitives && t != jsonNumberType {
+			c.declare(t)
+		}
+	}
+}
+
+// AllDeclarations declares every named type that has been added to the types
+// table (see Converter.AddTypes) with its own name (see Converter.TypeNamer),
+// and returns the typescript declarations for all declared types (see
+// Converter.File). Types added with any other name e.g. "string" or
+// "Uint8Array", and imported types, are not declared.
+func (c *Converter) AllDeclarations() string {
+	var types []reflect.Type
+	for t, name := range c.types {
+		// types added with another name e.g. "string" or "Uint8Array" refer to
+		// existing typescript types
+		if c.declared[t] || t.Name() == "" || name != c.typeName(t) {
+			continue
+		}
+		if _, ok := primitives[t]; ok {
+			continue
+		}
+		if _, ok := c.imports[t]; ok {
+			continue
+		}
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return c.types[types[i]] < c.types[types[j]] })
+	for _, t := range types {
+		// declared with the name it was added with
+		c.declared[t] = true
+		c.decls = append(c.decls, t)
+	}
+	return c.File()
+}
+
+// typeName returns the name used to declare or reference a named type.
+func (c *Converter) typeName(t reflect.Type) string {
+	if c.TypeNamer != nil {
+		return c.TypeNamer(t)
+	}
+	return identName(t)
+}
+
+var (
+	// qualifierRegexp matches package qualifiers e.g. "github.com/a/b."
+	qualifierRegexp = regexp.MustCompile(`[\w./-]+\.`)
+	identRegexp     = regexp.MustCompile(`\w+`)
+)
+
+// identName returns the golang name of a named type as a typescript
+// identifier. Instantiations of generic types are named e.g. Box[string], so
+// the type arguments are appended to the generic type name e.g. BoxString.
+func identName(t reflect.Type) string {
+	name := t.Name()
+	i := strings.Index(name, "[")
+	if i < 0 {
+		return name
+	}
+	args := qualifierRegexp.ReplaceAllString(name[i:], "")
+	name = name[:i]
+	for _, a := range identRegexp.FindAllString(args, -1) {
+		name += upperFirst(a)
+	}
+	return name
+}
+
+// declaration converts a declared type to a typescript declaration.
+func (c *Converter) declaration(t reflect.Type) string {
+	name := c.types[t]
+	if _, ok := c.enums[t]; ok {
+		return c.enumDeclaration(t)
+	}
+	if _, ok := c.unions[t]; ok {
+		return c.unionDeclaration(t)
+	}
+	// structs with a custom JSON encoding do not convert to an object type
+	if t.Kind() == reflect.Struct && t != timeType && !isMarshaler(t) && c.StructStyle == StructStyleInterface {
+		return fmt.Sprintf("export interface %s %s", name, c.convertKind(t))
+	}
+	return fmt.Sprintf("export type %s = %s", name, c.convertKind(t))
+}
+
+// declarations converts all declared types to typescript declarations.
+func (c *Converter) declarations() map[reflect.Type]string {
+	decls := make(map[reflect.Type]string)
+	// only imports used by the declarations are output
+	c.usedImports = make(map[reflect.Type]bool)
+	// converting a declaration may declare further types
+	for i := 0; i < len(c.decls); i++ {
+		decls[c.decls[i]] = c.declaration(c.decls[i])
+	}
+	return decls
+}
+
+// brands are the names of the branded number types, in output order.
+var brands = []string{"Int", "Float"}
+
+// brandDeclaration returns the declaration of a branded number type.
+func brandDeclaration(brand string) string {
+	return fmt.Sprintf("export type %s = number & { __%s: void }", brand, strings.ToLower(brand))
+}
+
+// Declarations returns the typescript declarations output by Converter.File,
+// keyed by name, so that they can be output to separate files.
+func (c *Converter) Declarations() map[string]string {
+	decls := make(map[string]string)
+	for t, d := range c.declarations() {
+		decls[c.types[t]] = d
+	}
+	for _, b := range brands {
+		if c.brands[b] {
+			decls[b] = brandDeclaration(b)
+		}
+	}
+	for _, g := range c.constGroups {
+		decls[g.name] = c.constGroupDeclaration(g)
+	}
+	return decls
+}
+
+// DeclarationIndex returns an interface declaration with the passed name that
+// maps the name of each declared type to the type e.g.
+// export interface Schema { User: User, Nested: Nested }.
+func (c *Converter) DeclarationIndex(name string) string {
+	// converting declarations may declare further types
+	c.declarations()
+	var props []string
+	for _, t := range c.decls {
+		props = append(props, fmt.Sprintf("%s: %s", propName(c.types[t]), c.types[t]))
+	}
+	if len(props) == 0 {
+		return fmt.Sprintf("export interface %s {}", name)
+	}
+	return fmt.Sprintf("export interface %s { %s }", name, strings.Join(props, ", "))
+}
+
+// File returns the typescript declarations for all declared types.
+func (c *Converter) File() string {
+	decls := c.declarations()
+
+	order := c.decls
+	if c.SortDeclarations {
+		order = c.sortDecls()
+	}
+
+	var out []string
+	for _, b := range brands {
+		if c.brands[b] {
+			out = append(out, brandDeclaration(b))
+		}
+	}
+	for _, t := range order {
+		out = append(out, decls[t])
+	}
+	for _, g := range c.constGroups {
+		out = append(out, c.constGroupDeclaration(g))
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	if imports := c.importStatements(); len(imports) > 0 {
+		out = append([]string{strings.Join(imports, "\n")}, out...)
+	}
+	return strings.Join(out, "\n\n") + "\n"
+}
+
+// sortDecls orders declared types so that dependencies come before the types
+// that use them.
+func (c *Converter) sortDecls() []reflect.Type {
+	var order []reflect.Type
+	visiting := make(map[reflect.Type]bool)
+	done := make(map[reflect.Type]bool)
+
+	var visit func(t reflect.Type)
+	visit = func(t reflect.Type) {
+		if done[t] || visiting[t] {
+			return // already sorted, or a cycle
+		}
+		visiting[t] = true
+		for _, d := range c.deps(t) {
+			visit(d)
+		}
+		done[t] = true
+		order = append(order, t)
+	}
+
+	for _, t := range c.decls {
+		visit(t)
+	}
+	return order
+}
+
+// deps finds the declared types that are referenced by the declaration for
+// the passed type.
+func (c *Converter) deps(t reflect.Type) []reflect.Type {
+	var deps []reflect.Type
+	seen := make(map[reflect.Type]bool)
+
+	var walk func(d reflect.Type)
+	walk = func(d reflect.Type) {
+		if seen[d] {
+			return
+		}
+		seen[d] = true
+		if d != t && c.declared[d] {
+			deps = append(deps, d)
+			return
+		}
+		for _, m := range c.unions[d] {
+			walk(m)
+		}
+		switch d.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+			walk(d.Elem())
+		case reflect.Map:
+			walk(d.Key())
+			walk(d.Elem())
+		case reflect.Struct:
+			for i := 0; i < d.NumField(); i++ {
+				walk(d.Field(i).Type)
+			}
+		case reflect.Func:
+			for i := 0; i < d.NumIn(); i++ {
+				walk(d.In(i))
+			}
+			for i := 0; i < d.NumOut(); i++ {
+				walk(d.Out(i))
+			}
+		}
+	}
+
+	walk(t)
+	return deps
+}
@@ -0,0 +1,133 @@
+package go2ts
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Const is a named constant value, for example a member of an enum.
+type Const struct {
+	Name  string
+	Value interface{}
+}
+
+// EnumStyle determines how enums are declared by the converter.
+type EnumStyle int
+
+const (
+	// EnumStyleEnum declares enums as typescript enums e.g.
+	// export enum Color { Red = "red", Green = "green" }
+	EnumStyleEnum EnumStyle = iota
+	// EnumStyleUnion declares enums as a union of their values e.g.
+	// export type Color = "red" | "green"
+	EnumStyleUnion
+	// EnumStyleUnionConst declares enums as a union of their values, along
+	// with a const object that maps member names to values e.g.
+	// export const Color = { Red: "red", Green: "green" } as const
+	EnumStyleUnionConst
+)
+
+// AddEnum adds a named type as an enum with the passed members. The type is
+// declared (see Converter.Declare) and is output as a typescript enum by
+// Converter.File. Members can be parsed from source using ParseEnums. An enum
+// with a single member is effectively a constant, so it is converted to the
+// literal value of the member e.g. "user".
+func (c *Converter) AddEnum(t reflect.Type, members []Const) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	c.enums[t] = members
+	c.declare(t)
+}
+
+// constGroup is a named group of constants output as a const object.
+type constGroup struct {
+	name   string
+	consts []Const
+}
+
+// AddConstGroup adds a named group of constants that is output by
+// Converter.File as a const object e.g.
+// export const Limits = { MaxUsers: 10, MaxTeams: 3 } as const
+func (c *Converter) AddConstGroup(name string, consts []Const) {
+	c.constGroups = append(c.constGroups, constGroup{name, consts})
+}
+
+// constGroupDeclaration converts a const group to a typescript declaration.
+func (c *Converter) constGroupDeclaration(g constGroup) string {
+	decl := fmt.Sprintf("export const %s = %s as const", g.name, constObject(g.consts))
+	if c.SatisfiesConstGroups {
+		decl += fmt.Sprintf(" satisfies Record<string, %s>", literalTypes(g.consts))
+	}
+	return decl
+}
+
+// enumDeclaration converts an enum to a typescript declaration.
+func (c *Converter) enumDeclaration(t reflect.Type) string {
+	name := c.types[t]
+	if c.EnumStyle == EnumStyleEnum {
+		var members []string
+		for _, m := range c.enums[t] {
+			members = append(members, fmt.Sprintf("%s = %s", m.Name, literal(m.Value)))
+		}
+		decl := fmt.Sprintf("export enum %s { %s }", name, strings.Join(members, ", "))
+		if c.EnumNameTypes {
+			decl += fmt.Sprintf("\nexport type %sName = keyof typeof %s", name, name)
+		}
+		return decl
+	}
+
+	var values []string
+	for _, m := range c.enums[t] {
+		values = append(values, literal(m.Value))
+	}
+	decl := fmt.Sprintf("export type %s = %s", name, strings.Join(values, " | "))
+	if c.EnumStyle == EnumStyleUnionConst {
+		decl += fmt.Sprintf("\nexport const %s = %s as const", name, constObject(c.enums[t]))
+	}
+	return decl
+}
+
+// constObject converts constants to a typescript object literal.
+func constObject(consts []Const) string {
+	var props []string
+	for _, k := range consts {
+		props = append(props, fmt.Sprintf("%s: %s", k.Name, literal(k.Value)))
+	}
+	return fmt.Sprintf("{ %s }", strings.Join(props, ", "))
+}
+
+// literalTypes returns the union of the primitive types of the constant
+// values e.g. number | string.
+func literalTypes(consts []Const) string {
+	var types []string
+	seen := make(map[string]bool)
+	for _, k := range consts {
+		ts := "number"
+		switch k.Value.(type) {
+		case string:
+			ts = "string"
+		case bool:
+			ts = "boolean"
+		}
+		if !seen[ts] {
+			seen[ts] = true
+			types = append(types, ts)
+		}
+	}
+	if len(types) == 0 {
+		return "never"
+	}
+	return strings.Join(types, " | ")
+}
+
+// literal converts a constant value to a typescript literal.
+func literal(v interface{}) string {
+	if s, ok := v.(string); ok {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
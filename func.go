@@ -3,6 +3,8 @@ package go2ts
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"unicode"
 )
 
@@ -17,6 +19,8 @@ type funcInfo struct {
 type param struct {
 	Name string
 	Type string
+	// Rest flags a rest parameter, converted from a variadic golang parameter.
+	Rest bool
 }
 
 var paramNames = map[reflect.Type]string{
@@ -36,6 +40,39 @@ var paramNames = map[reflect.Type]string{
 	reflect.TypeOf(""):         "str",
 }
 
+// isContext determines if a function parameter is a context i.e. it is
+// context.Context or an interface that embeds it. Types that are merely named
+// "Context" are not.
+func isContext(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && t.Implements(contextType)
+}
+
+// isStream determines if a function takes and returns channels, given its
+// (non context) params. A trailing error return is ignored.
+func isStream(t reflect.Type, ins []reflect.Type) bool {
+	outs := t.NumOut()
+	if outs > 0 && t.Out(outs-1).Implements(errorType) {
+		outs--
+	}
+	if outs != 1 || t.Out(0).Kind() != reflect.Chan {
+		return false
+	}
+	for _, in := range ins {
+		if in.Kind() == reflect.Chan {
+			return true
+		}
+	}
+	return false
+}
+
+// upperFirst returns the passed string with the first letter in upper case.
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[0:1]) + s[1:]
+}
+
 func isUpper(s string) bool {
 	for _, r := range s {
 		if !unicode.IsUpper(r) && unicode.IsLetter(r) {
@@ -71,3 +108,66 @@ func (finfo *funcInfo) appendParam(p param) {
 	}
 	finfo.Params = append(finfo.Params, p)
 }
+
+// returnObject converts return value types to an object type, keyed by the
+// passed names (quoted if necessary) or by the return value index if there is
+// no name.
+func returnObject(rets []string, names []string) string {
+	var props []string
+	for i, r := range rets {
+		name := strconv.Itoa(i)
+		if i < len(names) && names[i] != "" {
+			name = propName(names[i])
+		}
+		props = append(props, fmt.Sprintf("%s: %s", name, r))
+	}
+	return fmt.Sprintf("{ %s }", strings.Join(props, ", "))
+}
+
+// overload returns the subset of the function parameters with the passed
+// names, in parameter order.
+func (finfo *funcInfo) overload(names []string) []param {
+	var params []param
+	for _, p := range finfo.Params {
+		for _, n := range names {
+			if p.Name == n {
+				params = append(params, p)
+				break
+			}
+		}
+	}
+	if len(params) != len(names) {
+		panic(convertErrorf("unknown parameter in overload %v of %v", names, finfo.Params))
+	}
+	return params
+}
+
+// formatParams formats function parameters as a typescript parameter list.
+func formatParams(params []param) string {
+	var out []string
+	for _, p := range params {
+		if p.Rest {
+			out = append(out, fmt.Sprintf("...%s: %s", p.Name, p.Type))
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s: %s", p.Name, p.Type))
+	}
+	return strings.Join(out, ", ")
+}
+
+// destructure returns a destructuring pattern for the passed struct fields
+// e.g. { name, age }, or an empty string if the fields cannot be destructured
+// because a property name is not a valid identifier or is a reserved word.
+func destructure(fields []field) string {
+	var names []string
+	for _, f := range fields {
+		if f.Inline || !isIdent(f.Name) || reservedWords[f.Name] {
+			return ""
+		}
+		names = append(names, f.Name)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("{ %s }", strings.Join(names, ", "))
+}
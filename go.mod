@@ -1,3 +1,3 @@
 module github.com/alanshaw/go2ts
 
-go 1.15
+go 1.18
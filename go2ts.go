@@ -31,9 +31,12 @@
 package go2ts
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 var primitives = map[reflect.Type]string{
@@ -54,7 +57,20 @@ var primitives = map[reflect.Type]string{
 	reflect.TypeOf((*string)(nil)).Elem():  "string",
 }
 
+// primitiveKinds maps the kind of each primitive type to its typescript type.
+var primitiveKinds = func() map[reflect.Kind]string {
+	kinds := make(map[reflect.Kind]string)
+	for t, s := range primitives {
+		kinds[t.Kind()] = s
+	}
+	return kinds
+}()
+
 var errorType = reflect.TypeOf((*error)(nil)).Elem()
+var timeType = reflect.TypeOf(time.Time{})
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
 
 // FuncConf are configuration options that determine how a function is
 // converted into a typescript declaration by the converter.
@@ -66,22 +82,94 @@ type FuncConf struct {
 	// single return value. By default an array is only returned if there are 2+
 	// return values.
 	AlwaysArray bool
-	// NoIgnoreContext will include a context.Context param in the typescript
-	// function declaration if it is the first parameter. Default is to ignore it.
+	// NoIgnoreContext will include context.Context params in the typescript
+	// function declaration. Default is to ignore them, wherever they appear.
 	NoIgnoreContext bool
+	// OnlyIgnoreFirstContext ignores a context.Context param only if it is the
+	// first param, as is golang convention. Context params in other positions
+	// are included in the typescript function declaration.
+	OnlyIgnoreFirstContext bool
+	// NoCommaOk disables converting functions that return a value and a bool
+	// e.g. func() (T, bool) to return a nullable value i.e. T | null, so that
+	// the values are returned as an array instead. A trailing error return is
+	// removed first, so func() (T, bool, error) also returns T | null.
+	NoCommaOk bool
 	// IsMethod flags that the func is a method with a (ignored) receiver param
 	// and causes the converter to output a class method declaration.
 	IsMethod bool
 	// MethodName is the name of the method, used when FuncConf.IsMethod is true.
 	MethodName string
-	// ParamNames overrides default or global parameter names.
+	// ParamNames overrides default or global parameter names. Names are given
+	// in order for the params of the typescript function declaration i.e.
+	// excluding the receiver of a method and any ignored context params.
 	ParamNames []string
+	// ReturnNames are names for the return values, used when
+	// FuncConf.ReturnAsObject is true.
+	ReturnNames []string
+	// Overloads are the parameters of each overload signature that should be
+	// output for the function, given as lists of parameter names. Function
+	// types are output as an object type with a call signature per overload and
+	// methods are output once per overload (on separate lines).
+	Overloads [][]string
+	// ReturnAsObject causes multiple return values to be returned as an object
+	// keyed by FuncConf.ReturnNames (or the return value index if no name is
+	// given) instead of an array.
+	ReturnAsObject bool
+	// NonNull flags that the function never returns null, so that pointer
+	// return values are not nullable in PointerModeNull.
+	NonNull bool
 }
 
+// BoolMode determines how named boolean types (e.g. type Flag bool) are
+// converted by the converter.
+type BoolMode int
+
+const (
+	// BoolModePlain converts named booleans to boolean.
+	BoolModePlain BoolMode = iota
+	// BoolModeLiteral converts named booleans to the literal union true | false.
+	BoolModeLiteral
+	// BoolModeBranded converts named booleans to a branded boolean, for example
+	// boolean & { __brand: "Flag" }.
+	BoolModeBranded
+)
+
+// PointerMode determines how pointers are converted by the converter.
+type PointerMode int
+
+const (
+	// PointerModeElem converts pointers to their element type.
+	PointerModeElem PointerMode = iota
+	// PointerModeOptional converts pointer struct fields to optional properties.
+	// Other pointers are converted to their element type.
+	PointerModeOptional
+	// PointerModeNull converts pointers to a union of their element type and
+	// null e.g. T | null.
+	PointerModeNull
+)
+
 // Converter will convert a golang reflect.Type to Typescript type string.
 type Converter struct {
 	types      map[reflect.Type]string
 	paramNames map[reflect.Type]string
+	decls      []reflect.Type
+	declared   map[reflect.Type]bool
+	enums      map[reflect.Type][]Const
+	unions     map[reflect.Type][]reflect.Type
+	brands     map[string]bool
+	// imports are the types added by Converter.AddTypesWithImport and
+	// usedImports are the imported types referenced by the declarations
+	// (see Converter.declarations).
+	imports     map[reflect.Type]ImportedType
+	usedImports map[reflect.Type]bool
+	// constGroups are output as const objects by Converter.File.
+	constGroups []constGroup
+	// converting tracks named structs that are being converted, to detect
+	// recursion.
+	converting map[reflect.Type]bool
+	// convertingSource tracks named types parsed from source that are being
+	// converted (see Converter.ConvertPackage), keyed by type string.
+	convertingSource map[string]bool
 	// OnConvert is called when a type is converted but NOT present in the types
 	// table. It is safe (and expected) that Converter.AddTypes is called from
 	// this handler so that discovered types can be included in a converted type.
@@ -89,21 +177,204 @@ type Converter struct {
 	// ConfigureFunc is called for each function that is converted in order to set
 	// configuration options for how the typescript declaration should appear.
 	ConfigureFunc func(reflect.Type) FuncConf
+	// IntKeyMapsAsArrays converts maps with integer keys to arrays instead of
+	// objects, for use with tooling that renders such maps as JSON arrays.
+	IntKeyMapsAsArrays bool
+	// SparseArrays flags that arrays converted from integer keyed maps may have
+	// holes, so elements are optional i.e. Array<T | undefined>. Only applies
+	// when Converter.IntKeyMapsAsArrays is true.
+	SparseArrays bool
+	// RecordMaps converts maps to Record<string, T> instead of an object type
+	// with an index signature.
+	RecordMaps bool
+	// MapValueOptional flags that map values may be missing for any given key,
+	// as is the case under the typescript noUncheckedIndexedAccess option.
+	// Values are converted as T | undefined, or Partial<Record<string, T>> when
+	// combined with Converter.RecordMaps.
+	MapValueOptional bool
+	// NamedBoolMode determines how named boolean types are converted.
+	NamedBoolMode BoolMode
+	// SortDeclarations orders the declarations output by Converter.File so
+	// that types are declared before they are used. Declarations that form a
+	// cycle retain the order they were declared in.
+	SortDeclarations bool
+	// PointerMode determines how pointers are converted.
+	PointerMode PointerMode
+	// TypeNamer returns the typescript name for a named type. It is used
+	// wherever a named type is declared or referenced and can be used to
+	// produce unique names for types that share a name but are defined in
+	// different packages. Default uses the golang type name, with the type
+	// arguments of generic types appended e.g. Box[string] is named BoxString.
+	TypeNamer func(reflect.Type) string
+	// UnknownOpaqueStructs converts structs that have fields, but none that are
+	// exported, to Record<string, unknown> since their encoded shape cannot be
+	// known. Structs with no fields e.g. struct{} are still converted to {}.
+	UnknownOpaqueStructs bool
+	// BoolKeyLiterals converts maps with boolean keys to
+	// Record<"true" | "false", T> instead of an object with string keys.
+	BoolKeyLiterals bool
+	// FlattenPromises avoids redundant nesting of promises in async function
+	// returns. If the return type is already a Promise (e.g. a custom type has
+	// been added for a future) it is not wrapped in another Promise.
+	FlattenPromises bool
+	// NameStructParams declares named struct function parameters (see
+	// Converter.Declare) so that they are referenced by name instead of being
+	// inlined in the function declaration. Anonymous structs remain inline
+	// (see Converter.HoistStructParams).
+	NameStructParams bool
+	// HoistStructParams declares anonymous struct function parameters with at
+	// least this many fields, so that they are referenced by name instead of
+	// being inlined. The name is FuncConf.MethodName followed by the parameter
+	// name e.g. GetOptions, so only parameters named by FuncConf.ParamNames
+	// are hoisted. A number is appended to names that are already used by
+	// another type e.g. GetOptions1. Default is 0 i.e. no parameters are
+	// hoisted.
+	HoistStructParams int
+	// InlineStructParams destructures the fields of the param of functions
+	// that take a single struct param, unless the struct has a custom JSON
+	// encoding or typescript type (see Converter.AddTypes) e.g.
+	// ({ name, age }: { name: string, age: number }) => Promise<void>
+	InlineStructParams bool
+	// DeclareStructs declares every named struct that is converted (see
+	// Converter.Declare), including the type passed to Converter.Convert, so
+	// that structs are referenced by name and never inlined.
+	DeclareStructs bool
+	// Strict causes conversion to fail for kinds that are unlikely to be
+	// intended in a serialized type i.e. uintptr and unsafe.Pointer. Use
+	// Converter.TryConvert to receive an error instead of a panic.
+	Strict bool
+	// BrandedNumbers converts integer kinds to Int and float kinds to Float,
+	// so that integers and floats can be distinguished. Converter.File declares
+	// the branded types e.g. type Int = number & { __int: void }.
+	BrandedNumbers bool
+	// AllFieldsOptional makes all struct fields optional unless they are
+	// marked as required by a validator tag i.e. `validate:"required"`.
+	AllFieldsOptional bool
+	// AliasNamedTypes declares named map, slice and array types (see
+	// Converter.Declare) so that they are referenced by name as a type alias
+	// e.g. type Headers map[string]string is referenced as Headers.
+	AliasNamedTypes bool
+	// AliasNamedPrimitives declares named primitive types (see
+	// Converter.Declare) so that they are referenced by name as a type alias
+	// e.g. type Email string is referenced as Email. Go type aliases such as
+	// type Email = string are identical to the aliased type and are not
+	// declared.
+	AliasNamedPrimitives bool
+	// Indent causes structs to be output over multiple lines, with each field
+	// on its own line, indented by the passed string e.g. "  ". JSDoc comments
+	// for fields (e.g. from a `deprecated:"use Foo"` tag) are only output in
+	// multi-line mode.
+	Indent string
+	// TagParser parses a struct field tag, returning the field name (empty to
+	// use the golang field name), options (e.g. "omitempty") and whether the
+	// field should be skipped. It allows nonstandard tag syntaxes to be
+	// supported. Default parses the json tag as encoding/json does.
+	TagParser func(reflect.StructTag) (name string, opts []string, skip bool)
+	// WidenIndexSignatures widens the index signature of structs that have a
+	// catch-all map field (tagged `ts:",inline"`) to a union of the catch-all
+	// value type and the types of the other fields, so that the known fields
+	// are assignable to the index signature, as typescript requires.
+	WidenIndexSignatures bool
+	// FieldSeparator is the separator output after struct fields in multi-line
+	// mode (see Converter.Indent) e.g. ";". Default is ",".
+	FieldSeparator string
+	// TrailingComma outputs a separator after the last struct field in
+	// multi-line mode, for cleaner diffs.
+	TrailingComma bool
+	// EnumStyle determines how enums (see Converter.AddEnum) are declared.
+	EnumStyle EnumStyle
+	// EnumNameTypes declares a type for the member names of each enum declared
+	// in EnumStyleEnum, e.g. export type StateName = keyof typeof State.
+	EnumNameTypes bool
+	// StructStyle determines how structs are declared (see Converter.Declare).
+	StructStyle StructStyle
+	// SatisfiesConstGroups appends a satisfies clause to const groups (see
+	// Converter.AddConstGroup) e.g. as const satisfies Record<string, number>.
+	SatisfiesConstGroups bool
+	// SortMethods sorts methods by name in the output of
+	// Converter.ConvertMethodSet, for stable output when methods are renamed.
+	SortMethods bool
+	// AbstractClasses outputs an abstract class with abstract methods from
+	// Converter.ConvertMethodSet instead of a class declaration.
+	AbstractClasses bool
+	// ErrorOnlyReturns is the return type of functions whose only return value
+	// is an error (which is assumed to be thrown) e.g. "undefined" or "never".
+	// Default is "void".
+	ErrorOnlyReturns string
+	// DescriptionTag is the key of a struct tag whose value is output as the
+	// JSDoc comment for the field in multi-line mode (see Converter.Indent)
+	// e.g. "description" or "doc".
+	DescriptionTag string
+	// StrictOptional adds undefined to the type of optional properties e.g.
+	// name?: string | undefined, so that output is compatible with the
+	// typescript exactOptionalPropertyTypes setting. It also implies
+	// MapValueOptional and SparseArrays, so that map values and the elements
+	// of arrays converted from integer keyed maps are optional.
+	StrictOptional bool
+	// ValidatorTag is the key of the validator struct tag, used to determine
+	// required fields (see Converter.AllFieldsOptional) and the bounds of
+	// numeric fields, which are output as JSDoc @minimum and @maximum in
+	// multi-line mode. Default is "validate".
+	ValidatorTag string
+	// TimeType is the typescript type that time.Time is converted to e.g.
+	// "Date". Default is "string". The type of individual time.Time fields
+	// (or pointers, slices and arrays of time.Time) can be overridden with the
+	// ts tag e.g. `ts:"Date"`. The ts tag type is ignored for other fields.
+	TimeType string
+	// ArrayLengthComments outputs a comment with the length of fixed length
+	// arrays e.g. [3]int is converted to Array<number> /* length 3 */.
+	ArrayLengthComments bool
+	// StreamingFuncs converts functions that take and return channels e.g.
+	// func(<-chan Req) <-chan Resp to return an AsyncGenerator i.e.
+	// (req: AsyncIterable<Req>) => AsyncGenerator<Resp>.
+	StreamingFuncs bool
+	// ExhaustiveEnumKeys converts maps keyed by an enum (see Converter.AddEnum)
+	// to a Record that has every enum member as a key e.g. Record<1 | 2, T>.
+	// By default the Record is Partial, since golang maps may be sparse, but
+	// an exhaustive Record is more convenient if maps always have every key.
+	ExhaustiveEnumKeys bool
+	// Readonly makes all struct properties and the arrays of multiple function
+	// return values readonly. Individual fields can be made readonly with the
+	// ts tag option `ts:",readonly"`.
+	Readonly bool
+	// EmitTypeGuards outputs a type guard function for each member of a union
+	// (see Converter.AddUnion) that checks the value of the union
+	// discriminant e.g.
+	// export function isDog(x: Animal): x is Dog { return x.type === "Dog" }
+	EmitTypeGuards bool
+	// UnionDiscriminant is the name of the property whose value is the name of
+	// the union member, used by type guards. Default is "type".
+	UnionDiscriminant string
+	// InlineUnions references unions (see Converter.AddUnion) as the union of
+	// their members e.g. Dog | Cat, instead of by name.
+	InlineUnions bool
+	// OnError is called with non-fatal errors encountered during conversion,
+	// for example when ambiguous embedded struct fields are dropped.
+	OnError func(error)
 }
 
 // NewConverter creates a new converter instance with primitive types added.
 func NewConverter() *Converter {
 	c := Converter{
-		types:      make(map[reflect.Type]string),
-		paramNames: make(map[reflect.Type]string),
-		OnConvert:  func(reflect.Type, string) {},
+		types:            make(map[reflect.Type]string),
+		paramNames:       make(map[reflect.Type]string),
+		declared:         make(map[reflect.Type]bool),
+		enums:            make(map[reflect.Type][]Const),
+		unions:           make(map[reflect.Type][]reflect.Type),
+		brands:           make(map[string]bool),
+		converting:       make(map[reflect.Type]bool),
+		convertingSource: make(map[string]bool),
+		imports:          make(map[reflect.Type]ImportedType),
+		usedImports:      make(map[reflect.Type]bool),
+		OnConvert:        func(reflect.Type, string) {},
 	}
 	c.AddTypes(primitives)
 	c.AddParamNames(paramNames)
 	return &c
 }
 
-// AddTypes adds custom types.
+// AddTypes adds custom types. The typescript type may be any valid type
+// expression, including unions such as "string | { id: string }".
 func (c *Converter) AddTypes(customTypes map[reflect.Type]string) {
 	for k, v := range customTypes {
 		c.types[k] = v
@@ -126,56 +397,217 @@ func (c *Converter) AddParamNames(customParamNames map[reflect.Type]string) {
 // Assumes functions/methods are async so return values are all Promise<T>
 // and errors assumed to be thrown not returned.
 //
-// If a function returns multiple values they are returned as an array.
+// If a function returns multiple values they are returned as an array, except
+// for a value and a bool (comma-ok) which is returned as a nullable value.
 //
 // Context in function params is ignored.
 //
-// Recursion is NOT supported.
+// Recursive named structs are declared (see Converter.File) and referenced by
+// name.
 //
 // Interfaces are converted to any.
 //
+// time.Time is converted to string, or Converter.TimeType.
+//
+// json.Number is converted to number | string.
+//
+// Types that implement json.Marshaler are converted to unknown, unless they
+// are added to the types table (see Converter.AddTypes).
+//
+// []byte is converted to string since it is encoded as base64. Note that byte
+// arrays e.g. [8]byte are encoded as an array of numbers.
+//
+// Struct fields are named and omitted according to their json tags and
+// omitempty fields are optional. Fields of embedded structs are promoted to
+// the parent, other embedded types (e.g. interfaces, maps and slices) are
+// converted as a field named after the type, as they are encoded by
+// encoding/json.
+//
 // struct methods are NOT converted, but Converter.ConfigureFunc can be
 // used to create method declarations.
 func (c *Converter) Convert(t reflect.Type) (ts string) {
+	if c.Strict && (t.Kind() == reflect.Uintptr || t.Kind() == reflect.UnsafePointer) {
+		panic(convertErrorf("disallowed type in strict mode: %v (%s)", t, t.Kind()))
+	}
+
+	if _, ok := primitives[t]; ok && c.BrandedNumbers {
+		if ts = c.numberBrand(t); ts != "" {
+			return
+		}
+	}
+
+	// a single member enum is effectively a constant e.g. a union discriminant
+	if members := c.enums[t]; len(members) == 1 {
+		return literal(members[0].Value)
+	}
+
+	if c.InlineUnions {
+		if _, ok := c.unions[t]; ok {
+			return c.unionType(t)
+		}
+	}
+
 	ts, ok := c.types[t]
 	if ok {
+		if _, ok := c.imports[t]; ok {
+			c.usedImports[t] = true
+		}
 		return
 	}
 
-	kind := t.Kind()
-
-	// Handle type aliases
-	for t, s := range primitives {
-		if t.Kind() == kind {
-			ts = s
+	if c.DeclareStructs && t.Kind() == reflect.Struct {
+		c.declareStruct(t)
+		if ts, ok = c.types[t]; ok {
+			return
+		}
+	}
+	if c.AliasNamedTypes || c.AliasNamedPrimitives {
+		c.declareAlias(t)
+		if ts, ok = c.types[t]; ok {
 			return
 		}
 	}
 
-	defer func() { c.OnConvert(t, ts) }()
+	if t.Kind() == reflect.Struct && t.Name() != "" {
+		// a back-edge to a struct being converted, reference it by name
+		if c.converting[t] {
+			return c.declare(t)
+		}
+		c.converting[t] = true
+		defer delete(c.converting, t)
+	}
+
+	// Named primitive types e.g. type Flag bool are not passed to OnConvert
+	if _, ok := primitiveKinds[t.Kind()]; !ok {
+		defer func() { c.OnConvert(t, ts) }()
+	}
+	ts = c.convertKind(t)
+	if c.declared[t] {
+		ts = c.types[t]
+	}
+	return
+}
+
+// TryConvert is like Converter.Convert but returns an error instead of
+// panicking if the type cannot be converted.
+func (c *Converter) TryConvert(t reflect.Type) (ts string, err error) {
+	defer recoverConvertError(&err)
+	return c.Convert(t), nil
+}
+
+// convertError is the panic value for types that cannot be converted.
+type convertError struct{ error }
+
+// convertErrorf returns a convertError with a formatted message.
+func convertErrorf(format string, a ...interface{}) convertError {
+	return convertError{fmt.Errorf(format, a...)}
+}
+
+// recoverConvertError recovers a convertError panic and sets err to its
+// error. Other panics e.g. runtime errors are bugs, so are not recovered.
+func recoverConvertError(err *error) {
+	if r := recover(); r != nil {
+		e, ok := r.(convertError)
+		if !ok {
+			panic(r)
+		}
+		*err = e.error
+	}
+}
+
+// convertKind converts a type according to its kind, without consulting the
+// types table for the type itself.
+func (c *Converter) convertKind(t reflect.Type) (ts string) {
+	kind := t.Kind()
+
+	// json.Number is encoded as a number but is commonly decoded from strings
+	if t == jsonNumberType {
+		ts = "number | string"
+		return
+	}
+
+	// the encoding of a custom marshaler cannot be determined from the type
+	if t != timeType && isMarshaler(t) {
+		ts = "unknown"
+		return
+	}
+
+	// Handle named primitive types e.g. type Flag bool
+	if s, ok := primitiveKinds[kind]; ok {
+		ts = c.convertNamedPrimitive(t, s)
+		return
+	}
 
-	if kind == reflect.Ptr {
-		ts = c.convert(t.Elem())
+	if t == timeType {
+		ts = "string" // encoded as RFC 3339
+		if c.TimeType != "" {
+			ts = c.TimeType
+		}
+	} else if kind == reflect.Ptr {
+		ts = c.convertPtr(t)
 	} else if kind == reflect.Chan {
 		ts = fmt.Sprintf("AsyncIterable<%s>", c.convert(t.Elem()))
 	} else if kind == reflect.Func {
 		ts = c.convertFunc(t)
 	} else if kind == reflect.Struct {
 		ts = c.convertStruct(t)
+	} else if kind == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		ts = "string" // []byte is encoded as a base64 string
 	} else if kind == reflect.Slice || kind == reflect.Array {
 		ts = fmt.Sprintf("Array<%s>", c.convert(t.Elem()))
+		if kind == reflect.Array && c.ArrayLengthComments {
+			ts += fmt.Sprintf(" /* length %d */", t.Len())
+		}
 	} else if kind == reflect.Map {
-		ts = fmt.Sprintf("{ [k: string]: %s }", c.convert(t.Elem()))
+		ts = c.convertMap(t)
 	} else if kind == reflect.Interface {
 		ts = "any"
 	} else {
-		panic(fmt.Errorf("unhandled type: %v (%s)", t, t.Kind()))
+		panic(convertErrorf("unhandled type: %v (%s)", t, t.Kind()))
 	}
 	return
 }
 
+// convertNamedPrimitive converts a named type whose underlying type is a
+// primitive, given the typescript type of the primitive.
+func (c *Converter) convertNamedPrimitive(t reflect.Type, ts string) string {
+	if c.BrandedNumbers {
+		if b := c.numberBrand(t); b != "" {
+			return b
+		}
+	}
+	if t.Kind() == reflect.Bool {
+		switch c.NamedBoolMode {
+		case BoolModeLiteral:
+			return "true | false"
+		case BoolModeBranded:
+			return fmt.Sprintf("boolean & { __brand: %q }", c.typeName(t))
+		}
+	}
+	return ts
+}
+
+// numberBrand returns the branded number type for integer and float kinds, or
+// an empty string for other kinds.
+func (c *Converter) numberBrand(t reflect.Type) string {
+	var brand string
+	if isInt(t.Kind()) {
+		brand = "Int"
+	} else if t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64 {
+		brand = "Float"
+	} else {
+		return ""
+	}
+	c.brands[brand] = true
+	return brand
+}
+
 func (c *Converter) convert(t reflect.Type) string {
+	_, known := c.types[t]
 	ts := c.Convert(t)
+	if known {
+		return ts
+	}
 	// re-check against types: OnConvert may have called AddTypes
 	uts, ok := c.types[t]
 	if ok {
@@ -194,14 +626,35 @@ func (c *Converter) extractFunc(t reflect.Type, fconf FuncConf) *funcInfo {
 			if i == t.NumOut()-1 && out.Implements(errorType) {
 				break // skip last param if error
 			}
-			rets = append(rets, c.convert(out))
+			ret := c.convert(out)
+			if fconf.NonNull && out.Kind() == reflect.Ptr && !isFuncType(ret) {
+				ret = strings.TrimSuffix(ret, " | null")
+			}
+			rets = append(rets, ret)
+		}
+
+		// comma-ok returns e.g. (T, bool) return null when not ok
+		if len(rets) == 2 && t.Out(1).Kind() == reflect.Bool && !fconf.NoCommaOk {
+			rets = []string{rets[0]}
+			if !strings.HasSuffix(rets[0], " | null") || isFuncType(rets[0]) {
+				rets[0] = union(rets[0], "null")
+			}
 		}
 
 		// If only 1 value just return it, if more than 1 we need to wrap in array.
 		if (len(rets) > 0 && fconf.AlwaysArray) || len(rets) > 1 {
-			finfo.Returns = fmt.Sprintf("[%s]", strings.Join(rets, ", "))
+			if fconf.ReturnAsObject {
+				finfo.Returns = returnObject(rets, fconf.ReturnNames)
+			} else {
+				finfo.Returns = fmt.Sprintf("[%s]", strings.Join(rets, ", "))
+				if c.Readonly {
+					finfo.Returns = "readonly " + finfo.Returns
+				}
+			}
 		} else if len(rets) == 1 {
 			finfo.Returns = rets[0]
+		} else if c.ErrorOnlyReturns != "" {
+			finfo.Returns = c.ErrorOnlyReturns
 		}
 	}
 
@@ -209,23 +662,43 @@ func (c *Converter) extractFunc(t reflect.Type, fconf FuncConf) *funcInfo {
 	if fconf.IsMethod {
 		start = 1 // first argument is receiver, so skip over this
 	}
+	var ins []reflect.Type
 	for i := start; i < t.NumIn(); i++ {
 		in := t.In(i)
-		// skip context if method takes one
-		if in.Name() == "Context" && !fconf.NoIgnoreContext {
+		// skip context if method takes one, in any position
+		if isContext(in) && !fconf.NoIgnoreContext && (i == start || !fconf.OnlyIgnoreFirstContext) {
 			continue
 		}
 		var name string
-		if len(fconf.ParamNames) > i {
-			name = fconf.ParamNames[i]
+		// names are for the params in the declaration i.e. excluding the
+		// receiver and any ignored context params
+		if n := len(ins); len(fconf.ParamNames) > n {
+			name = fconf.ParamNames[n]
+			c.hoistStructParam(in, upperFirst(fconf.MethodName)+upperFirst(name))
 		} else {
 			name = c.paramName(in)
 		}
-		p := param{name, c.convert(in)}
+		if c.NameStructParams {
+			c.declareStruct(in)
+		}
+		// variadic params are passed as a slice of the element type
+		rest := t.IsVariadic() && i == t.NumIn()-1
+		p := param{name, c.convert(in), rest}
 		finfo.appendParam(p)
+		ins = append(ins, in)
+	}
+	if c.InlineStructParams && len(ins) == 1 && c.isObjectType(ins[0]) {
+		if names := destructure(c.structFields(ins[0])); names != "" {
+			finfo.Params[0].Name = names
+		}
 	}
 
-	if !fconf.IsSync {
+	if c.StreamingFuncs && isStream(t, ins) {
+		finfo.Returns = fmt.Sprintf("AsyncGenerator<%s>", c.convert(t.Out(0).Elem()))
+		return &finfo
+	}
+
+	if !fconf.IsSync && !(c.FlattenPromises && isGeneric(finfo.Returns, "Promise")) {
 		finfo.Returns = fmt.Sprintf("Promise<%s>", finfo.Returns)
 	}
 	return &finfo
@@ -237,10 +710,11 @@ func (c *Converter) paramName(t reflect.Type) string {
 	if ok {
 		return name
 	}
-	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
 		return c.paramName(t.Elem())
 	}
-	name = t.Name()
+	name = identName(t)
 	if name == "" {
 		name = "_"
 	} else {
@@ -259,39 +733,496 @@ func (c *Converter) convertFunc(t reflect.Type) string {
 	if c.ConfigureFunc != nil {
 		fconf = c.ConfigureFunc(t)
 	}
+	return c.funcDeclaration(t, fconf)
+}
+
+// funcDeclaration converts a function type to a typescript declaration using
+// the passed configuration.
+func (c *Converter) funcDeclaration(t reflect.Type, fconf FuncConf) string {
 	finfo := c.extractFunc(t, fconf)
-	var params []string
-	for _, p := range finfo.Params {
-		params = append(params, fmt.Sprintf("%s: %s", p.Name, p.Type))
+	if len(fconf.Overloads) == 0 {
+		params := formatParams(finfo.Params)
+		if fconf.IsMethod {
+			return fmt.Sprintf("%s (%s): %s", fconf.MethodName, params, finfo.Returns)
+		}
+		return fmt.Sprintf("(%s) => %s", params, finfo.Returns)
+	}
+
+	var sigs []string
+	for _, names := range fconf.Overloads {
+		params := formatParams(finfo.overload(names))
+		if fconf.IsMethod {
+			sigs = append(sigs, fmt.Sprintf("%s (%s): %s", fconf.MethodName, params, finfo.Returns))
+		} else {
+			sigs = append(sigs, fmt.Sprintf("(%s): %s", params, finfo.Returns))
+		}
 	}
 	if fconf.IsMethod {
-		return fmt.Sprintf("%s (%s): %s", fconf.MethodName, strings.Join(params, ", "), finfo.Returns)
+		return strings.Join(sigs, "\n")
 	}
-	return fmt.Sprintf("(%s) => %s", strings.Join(params, ", "), finfo.Returns)
+	return fmt.Sprintf("{ %s }", strings.Join(sigs, "; "))
 }
 
 // extractStruct extracts typescript type information about a struct.
+//
+// Whether a field is optional and/or nullable depends on whether it is a
+// pointer, has the omitempty tag option and on Converter.PointerMode:
+//
+//	field          | PointerModeElem | PointerModeOptional | PointerModeNull
+//	T              | a: T            | a: T                | a: T
+//	T omitempty    | a?: T           | a?: T               | a?: T
+//	*T             | a: T            | a?: T               | a: T | null
+//	*T omitempty   | a?: T           | a?: T               | a?: T | null
 func (c *Converter) extractStruct(t reflect.Type) *structInfo {
-	sinfo := structInfo{Name: t.Name()}
+	sinfo := structInfo{Name: t.Name(), Fields: c.structFields(t)}
+	for i, f := range sinfo.Fields {
+		sinfo.Fields[i].Type = c.fieldType(f)
+	}
+	return &sinfo
+}
+
+// structFields extracts the fields of a struct, without converting their
+// types. As with encoding/json, the fields of embedded structs are promoted
+// and other embedded types (e.g. interfaces) are fields named after the type.
+//
+// Where promoted fields share a name, the least nested field is used. If there
+// are multiple at the same depth, the one with a tag name is used, otherwise
+// they are ambiguous and are all dropped, matching encoding/json.
+func (c *Converter) structFields(t reflect.Type) []field {
+	fields := c.appendFields(nil, t, 0, map[reflect.Type]bool{t: true})
+
+	byName := make(map[string][]int)
+	for i, f := range fields {
+		byName[f.Name] = append(byName[f.Name], i)
+	}
+	var dominant []field
+	for i, f := range fields {
+		idxs := byName[f.Name]
+		if len(idxs) == 1 {
+			dominant = append(dominant, f)
+			continue
+		}
+		d, ok := dominantField(fields, idxs)
+		if ok && d == i {
+			dominant = append(dominant, f)
+		} else if !ok && idxs[0] == i && c.OnError != nil {
+			c.OnError(fmt.Errorf("dropped ambiguous field %q in %v", f.Name, t))
+		}
+	}
+	return dominant
+}
+
+// dominantField returns the index of the dominant field of those with the
+// same name, given their indexes, or false if there is no dominant field.
+func dominantField(fields []field, idxs []int) (int, bool) {
+	var min []int
+	for _, i := range idxs {
+		if len(min) == 0 || fields[i].Depth < fields[min[0]].Depth {
+			min = []int{i}
+		} else if fields[i].Depth == fields[min[0]].Depth {
+			min = append(min, i)
+		}
+	}
+	if len(min) == 1 {
+		return min[0], true
+	}
+	var tagged []int
+	for _, i := range min {
+		if fields[i].Tagged {
+			tagged = append(tagged, i)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return 0, false
+}
+
+// isOptional determines if a struct field is optional (see
+// Converter.extractStruct), given its json tag options.
+func (c *Converter) isOptional(f reflect.StructField, opts []string) bool {
+	if hasOption(opts, "omitempty") {
+		return true
+	}
+	if f.Type.Kind() == reflect.Ptr && c.PointerMode == PointerModeOptional {
+		return true
+	}
+	return c.AllFieldsOptional && !hasOption(c.validatorOptions(f.Tag), "required")
+}
+
+func (c *Converter) appendFields(fields []field, t reflect.Type, depth int, visiting map[reflect.Type]bool) []field {
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
+		name, opts, skip := c.parseTag(f.Tag)
+		if skip {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && ft != timeType {
+				if !visiting[ft] {
+					visiting[ft] = true
+					fields = c.appendFields(fields, ft, depth+1, visiting)
+					delete(visiting, ft)
+				}
+				continue
+			}
+		}
 		if !isUpper(f.Name[0:1]) {
 			continue
 		}
-		sinfo.Fields = append(sinfo.Fields, field{Name: f.Name, Type: c.convert(f.Type)})
+		tagged := name != ""
+		if !tagged {
+			name = f.Name
+		}
+		optional := c.isOptional(f, opts)
+		tsType, tsOpts := parseTSTag(f.Tag)
+		fi := field{
+			Name:     name,
+			GoType:   f.Type,
+			Optional: optional,
+			Quoted:   hasOption(opts, "string") && isQuotable(f.Type),
+			Inline:   hasOption(tsOpts, "inline") && f.Type.Kind() == reflect.Map,
+			Readonly: c.Readonly || hasOption(tsOpts, "readonly"),
+			TSType:   tsType,
+			Depth:    depth,
+			Tagged:   tagged,
+		}
+		if d, ok := f.Tag.Lookup("deprecated"); ok {
+			fi.Deprecated = &d
+		}
+		if c.DescriptionTag != "" {
+			fi.Description = f.Tag.Get(c.DescriptionTag)
+		}
+		if isNumber(f.Type) {
+			vopts := c.validatorOptions(f.Tag)
+			fi.Minimum, _ = validatorParam(vopts, "min")
+			fi.Maximum, _ = validatorParam(vopts, "max")
+		}
+		fields = append(fields, fi)
 	}
-	return &sinfo
+	return fields
+}
+
+// fieldType converts the type of a struct field to a typescript type.
+func (c *Converter) fieldType(f field) string {
+	if f.TSType != "" {
+		if ts, ok := c.fieldTimeType(f.GoType, f.TSType); ok {
+			return ts
+		}
+	}
+	if f.Inline {
+		return c.convert(f.GoType.Elem())
+	}
+	if !f.Quoted {
+		return c.convert(f.GoType)
+	}
+	ts := "string" // value is encoded within a JSON string
+	if f.GoType.Kind() == reflect.Ptr && c.PointerMode == PointerModeNull {
+		ts = union(ts, "null")
+	}
+	return ts
+}
+
+// fieldTimeType converts a field type that is time.Time, or an unnamed
+// pointer, slice or array of time.Time, using the passed typescript type for
+// time.Time e.g. Array<Date>. It returns false for other field types.
+func (c *Converter) fieldTimeType(t reflect.Type, ts string) (string, bool) {
+	if t == timeType {
+		return ts, true
+	}
+	if t.Name() != "" {
+		return "", false
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem, ok := c.fieldTimeType(t.Elem(), ts)
+		if c.PointerMode == PointerModeNull && !strings.HasSuffix(elem, " | null") {
+			elem = union(elem, "null")
+		}
+		return elem, ok
+	case reflect.Slice, reflect.Array:
+		elem, ok := c.fieldTimeType(t.Elem(), ts)
+		return fmt.Sprintf("Array<%s>", elem), ok
+	}
+	return "", false
+}
+
+// parseTag parses a struct field tag using Converter.TagParser, if set.
+func (c *Converter) parseTag(tag reflect.StructTag) (name string, opts []string, skip bool) {
+	if c.TagParser != nil {
+		return c.TagParser(tag)
+	}
+	return parseJSONTag(tag)
 }
 
 // convertStruct converts a struct to a typescript declaration.
 func (c *Converter) convertStruct(t reflect.Type) string {
 	sinfo := c.extractStruct(t)
 	if len(sinfo.Fields) == 0 {
+		if c.UnknownOpaqueStructs && isOpaque(t) {
+			return "Record<string, unknown>"
+		}
 		return "{}"
 	}
 	var fields []string
 	for _, f := range sinfo.Fields {
-		fields = append(fields, fmt.Sprintf("%s: %s", f.Name, f.Type))
+		name := propName(f.Name)
+		ts := f.Type
+		if f.Optional {
+			name += "?"
+			if c.StrictOptional && !strings.HasSuffix(ts, " | undefined") {
+				ts = union(ts, "undefined")
+			}
+		}
+		prop := fmt.Sprintf("%s: %s", name, ts)
+		if f.Inline {
+			prop = fmt.Sprintf("[k: string]: %s", c.indexType(sinfo, f))
+		}
+		if f.Readonly {
+			prop = "readonly " + prop
+		}
+		if c.Indent != "" {
+			prop = strings.ReplaceAll(prop, "\n", "\n"+c.Indent)
+			if doc := fieldDoc(f); len(doc) > 0 {
+				prop = jsdoc(doc, c.Indent) + "\n" + c.Indent + prop
+			}
+		}
+		fields = append(fields, prop)
+	}
+	if c.Indent != "" {
+		sep := c.FieldSeparator
+		if sep == "" {
+			sep = ","
+		}
+		trailing := ""
+		if c.TrailingComma {
+			trailing = sep
+		}
+		return fmt.Sprintf("{\n%s%s%s\n}", c.Indent, strings.Join(fields, sep+"\n"+c.Indent), trailing)
 	}
 	return fmt.Sprintf("{ %s }", strings.Join(fields, ", "))
 }
+
+// indexType returns the value type for the index signature of a struct, given
+// its catch-all field.
+func (c *Converter) indexType(sinfo *structInfo, catchall field) string {
+	if !c.WidenIndexSignatures || catchall.Type == "unknown" || catchall.Type == "any" {
+		return catchall.Type
+	}
+	var types []string
+	seen := make(map[string]bool)
+	add := func(ts string) {
+		if !seen[ts] {
+			seen[ts] = true
+			types = append(types, ts)
+		}
+	}
+	for _, f := range sinfo.Fields {
+		if !f.Inline {
+			add(f.Type)
+			if f.Optional {
+				add("undefined")
+			}
+		}
+	}
+	add(catchall.Type)
+	return union(types...)
+}
+
+// fieldDoc returns the lines of the JSDoc comment for a struct field.
+func fieldDoc(f field) []string {
+	var doc []string
+	if f.Description != "" {
+		doc = append(doc, strings.Split(f.Description, "\n")...)
+	}
+	if f.Minimum != "" {
+		doc = append(doc, "@minimum "+f.Minimum)
+	}
+	if f.Maximum != "" {
+		doc = append(doc, "@maximum "+f.Maximum)
+	}
+	if f.Deprecated != nil {
+		doc = append(doc, strings.TrimSpace("@deprecated "+*f.Deprecated))
+	}
+	return doc
+}
+
+// jsdoc formats lines as a JSDoc comment, where lines after the first are
+// indented by the passed string.
+func jsdoc(lines []string, indent string) string {
+	if len(lines) == 1 {
+		return fmt.Sprintf("/** %s */", lines[0])
+	}
+	out := "/**"
+	for _, l := range lines {
+		out += strings.TrimRight(fmt.Sprintf("\n%s * %s", indent, l), " ")
+	}
+	return out + fmt.Sprintf("\n%s */", indent)
+}
+
+// convertPtr converts a pointer to a typescript declaration.
+func (c *Converter) convertPtr(t reflect.Type) string {
+	ts := c.convert(t.Elem())
+	// a function type returning a nullable value is not itself nullable
+	if c.PointerMode == PointerModeNull && (!strings.HasSuffix(ts, " | null") || isFuncType(ts)) {
+		ts = union(ts, "null")
+	}
+	return ts
+}
+
+// convertMap converts a map to a typescript declaration.
+func (c *Converter) convertMap(t reflect.Type) string {
+	// maps keyed by an enum may only have some of its members as keys
+	if members, ok := c.enums[t.Key()]; ok {
+		var keys []string
+		for _, m := range members {
+			keys = append(keys, literal(m.Value))
+		}
+		if c.ExhaustiveEnumKeys {
+			return c.record(strings.Join(keys, " | "), c.convert(t.Elem()))
+		}
+		return fmt.Sprintf("Partial<Record<%s, %s>>", strings.Join(keys, " | "), c.convert(t.Elem()))
+	}
+	if c.IntKeyMapsAsArrays && isInt(t.Key().Kind()) {
+		elem := c.convert(t.Elem())
+		if c.SparseArrays || c.StrictOptional {
+			elem = union(elem, "undefined")
+		}
+		return fmt.Sprintf("Array<%s>", elem)
+	}
+	if c.BoolKeyLiterals && t.Key().Kind() == reflect.Bool {
+		return c.record("\"true\" | \"false\"", c.convert(t.Elem()))
+	}
+	// JSON object keys are always strings
+	if c.RecordMaps {
+		return c.record("string", c.convert(t.Elem()))
+	}
+	elem := c.convert(t.Elem())
+	if c.MapValueOptional || c.StrictOptional {
+		elem = union(elem, "undefined")
+	}
+	return fmt.Sprintf("{ [k: string]: %s }", elem)
+}
+
+// record returns a Record type for the passed key and value types, which is
+// Partial if map values are optional.
+func (c *Converter) record(key string, value string) string {
+	r := fmt.Sprintf("Record<%s, %s>", key, value)
+	if c.MapValueOptional || c.StrictOptional {
+		r = fmt.Sprintf("Partial<%s>", r)
+	}
+	return r
+}
+
+// isObjectType determines if the passed type is a struct that converts to an
+// object type with its fields, or to the declared name of one. Structs with a
+// custom JSON encoding or added with a custom typescript type do not.
+func (c *Converter) isObjectType(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType || isMarshaler(t) {
+		return false
+	}
+	_, custom := c.types[t]
+	return !custom || c.declared[t]
+}
+
+// isMarshaler determines if the passed type, or a pointer to it, implements
+// json.Marshaler and so has a custom JSON encoding.
+func isMarshaler(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return false
+	}
+	return t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType)
+}
+
+// isNumber determines if the passed type (or pointer to type) is an integer or
+// float.
+func isNumber(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return isInt(t.Kind()) || t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+}
+
+// isInt determines if the passed kind is a signed or unsigned integer.
+func isInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// union joins the passed types into a union type. Function types are wrapped
+// in parens so that the union does not apply to their return type.
+func union(types ...string) string {
+	for i, ts := range types {
+		if isFuncType(ts) {
+			types[i] = fmt.Sprintf("(%s)", ts)
+		}
+	}
+	return strings.Join(types, " | ")
+}
+
+// isFuncType determines if the passed typescript type is a function type i.e.
+// it contains a top level "=>".
+func isFuncType(ts string) bool {
+	depth := 0
+	for i, r := range ts {
+		switch r {
+		case '(', '<', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+		case '>':
+			if i > 0 && ts[i-1] == '=' {
+				if depth == 0 {
+					return true
+				}
+			} else {
+				depth--
+			}
+		}
+	}
+	return false
+}
+
+// isOpaque determines if the passed struct type has fields but none of them
+// are exported.
+func isOpaque(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			return false
+		}
+	}
+	return t.NumField() > 0
+}
+
+// isGeneric determines if the passed typescript type is an instance of the
+// named generic type e.g. Promise<T>.
+func isGeneric(ts string, name string) bool {
+	if !strings.HasPrefix(ts, name+"<") || !strings.HasSuffix(ts, ">") {
+		return false
+	}
+	depth := 0
+	for i, r := range ts[len(name):] {
+		switch r {
+		case '<':
+			depth++
+		case '>':
+			if i > 0 && ts[len(name)+i-1] == '=' {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				return len(name)+i == len(ts)-1
+			}
+		}
+	}
+	return false
+}
@@ -2,8 +2,16 @@ package go2ts
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/mail"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
+	"unsafe"
 )
 
 func expect(t *testing.T, actual string, expected string) {
@@ -18,6 +26,8 @@ func typ(i interface{}) reflect.Type {
 }
 
 type User struct{ Name string }
+type Flag bool
+type Email string
 type Nested struct{ Owner User }
 
 func (*Nested) Method(arg string) {}
@@ -73,12 +83,106 @@ func TestFuncs(t *testing.T) {
 	expect(t, c.Convert(typ(func(ctx context.Context) {})), "(context: any) => void")
 }
 
+type Server struct{ Addr string }
+type Option func(*Server)
+
+func TestInlineStructParams(t *testing.T) {
+	type Options struct {
+		Name string `json:"name"`
+		Age  int    `json:"age,omitempty"`
+	}
+	c := NewConverter()
+	c.InlineStructParams = true
+	expect(t, c.Convert(typ(func(Options) {})), "({ name, age }: { name: string, age?: number }) => Promise<void>")
+	expect(t, c.Convert(typ(func(context.Context, Options) error { return nil })), "({ name, age }: { name: string, age?: number }) => Promise<void>")
+	expect(t, c.Convert(typ(func(Options, int) {})), "(options: { name: string, age?: number }, int: number) => Promise<void>")
+	expect(t, c.Convert(typ(func(struct{}) {})), "(_: {}) => Promise<void>")
+	type Reserved struct {
+		Default string `json:"default"`
+		Class   int    `json:"class"`
+	}
+	expect(t, c.Convert(typ(func(Reserved) {})), "(reserved: { default: string, class: number }) => Promise<void>")
+	expect(t, c.Convert(typ(func(Money) {})), "(money: unknown) => Promise<void>")
+	c.AddTypes(map[reflect.Type]string{typ(Server{}): "string | { id: string }"})
+	expect(t, c.Convert(typ(func(Server) {})), "(server: string | { id: string }) => Promise<void>")
+	c.Declare(typ(User{}))
+	expect(t, c.Convert(typ(func(User) {})), "({ Name }: User) => Promise<void>")
+	for _, fn := range []interface{}{func(Options) {}, func(Reserved) {}} {
+		if err := Validate(c.Convert(typ(fn))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFuncsVariadic(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(func(string, ...int) {})), "(str: string, ...int: Array<number>) => Promise<void>")
+	expect(t, c.Convert(typ(func(...Option) *Server { return nil })), "(...option: Array<(server: { Addr: string }) => Promise<void>>) => Promise<{ Addr: string }>")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf {
+		if t.IsVariadic() {
+			return FuncConf{IsSync: true, ParamNames: []string{"opts"}}
+		}
+		return FuncConf{IsSync: true}
+	}
+	expect(t, c.Convert(typ(func(...Option) *Server { return nil })), "(...opts: Array<(server: { Addr: string }) => void>) => { Addr: string }")
+	if err := Validate(c.Convert(typ(func(...Option) *Server { return nil }))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFuncsCommaOk(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(func() (User, error) { return User{}, nil })), "() => Promise<{ Name: string }>")
+	expect(t, c.Convert(typ(func() (User, bool) { return User{}, false })), "() => Promise<{ Name: string } | null>")
+	expect(t, c.Convert(typ(func() (User, bool, error) { return User{}, false, nil })), "() => Promise<{ Name: string } | null>")
+	expect(t, c.Convert(typ(func() (string, int, bool) { return "", 0, false })), "() => Promise<[string, number, boolean]>")
+	expect(t, c.Convert(typ(func() (bool, error) { return false, nil })), "() => Promise<boolean>")
+	c.PointerMode = PointerModeNull
+	expect(t, c.Convert(typ(func() (*User, bool) { return nil, false })), "() => Promise<{ Name: string } | null>")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{NoCommaOk: true} }
+	expect(t, c.Convert(typ(func() (User, bool) { return User{}, false })), "() => Promise<[{ Name: string }, boolean]>")
+}
+
+func TestStreamingFuncs(t *testing.T) {
+	c := NewConverter()
+	stream := func(context.Context, <-chan User) (<-chan Nested, error) { return nil, nil }
+	expect(t, c.Convert(typ(stream)), "(user: AsyncIterable<{ Name: string }>) => Promise<AsyncIterable<{ Owner: { Name: string } }>>")
+	c.StreamingFuncs = true
+	expect(t, c.Convert(typ(stream)), "(user: AsyncIterable<{ Name: string }>) => AsyncGenerator<{ Owner: { Name: string } }>")
+	expect(t, c.Convert(typ(func() chan string { return nil })), "() => Promise<AsyncIterable<string>>")
+	expect(t, c.Convert(typ(func(chan string) {})), "(str: AsyncIterable<string>) => Promise<void>")
+}
+
+func TestErrorOnlyReturns(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(func() error { return nil })), "() => Promise<void>")
+	expect(t, c.Convert(typ(func() {})), "() => Promise<void>")
+	c.ErrorOnlyReturns = "never"
+	expect(t, c.Convert(typ(func() error { return nil })), "() => Promise<never>")
+	expect(t, c.Convert(typ(func() (int, error) { return 0, nil })), "() => Promise<number>")
+	expect(t, c.Convert(typ(func() {})), "() => Promise<void>")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{IsSync: true} }
+	expect(t, c.Convert(typ(func() error { return nil })), "() => never")
+	expect(t, c.Convert(typ(func() (int, error) { return 0, nil })), "() => number")
+}
+
 func TestSlices(t *testing.T) {
 	c := NewConverter()
 	expect(t, c.Convert(typ([]string{})), "Array<string>")
 	expect(t, c.Convert(typ([]*User{})), "Array<{ Name: string }>")
 }
 
+func TestArrayLengthComments(t *testing.T) {
+	c := NewConverter()
+	c.ArrayLengthComments = true
+	expect(t, c.Convert(typ([3]int{})), "Array<number> /* length 3 */")
+	expect(t, c.Convert(typ([]int{})), "Array<number>")
+	expect(t, c.Convert(typ(struct{ RGB [3]uint8 }{})), "{ RGB: Array<number> /* length 3 */ }")
+	if err := Validate(c.Convert(typ(struct{ RGB [3]uint8 }{}))); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestArrays(t *testing.T) {
 	c := NewConverter()
 	expect(t, c.Convert(typ([2]string{"first", "second"})), "Array<string>")
@@ -89,3 +193,1108 @@ func TestMaps(t *testing.T) {
 	expect(t, c.Convert(typ(map[string]int{})), "{ [k: string]: number }")
 	expect(t, c.Convert(typ(map[string]User{})), "{ [k: string]: { Name: string } }")
 }
+
+func TestIntKeyMaps(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(map[int]string{})), "{ [k: string]: string }")
+	c.IntKeyMapsAsArrays = true
+	expect(t, c.Convert(typ(map[int]string{})), "Array<string>")
+	expect(t, c.Convert(typ(map[uint8]User{})), "Array<{ Name: string }>")
+	expect(t, c.Convert(typ(map[string]int{})), "{ [k: string]: number }")
+	c.SparseArrays = true
+	expect(t, c.Convert(typ(map[int]string{})), "Array<string | undefined>")
+}
+
+func TestNamedPrimitives(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(Email(""))), "string")
+	expect(t, c.Convert(typ(Flag(false))), "boolean")
+	expect(t, c.Convert(typ(false)), "boolean")
+	c.NamedBoolMode = BoolModeLiteral
+	expect(t, c.Convert(typ(Flag(false))), "true | false")
+	expect(t, c.Convert(typ(false)), "boolean")
+	expect(t, c.Convert(typ(struct{ On Flag }{})), "{ On: true | false }")
+	c.NamedBoolMode = BoolModeBranded
+	expect(t, c.Convert(typ(Flag(false))), "boolean & { __brand: \"Flag\" }")
+	expect(t, c.Convert(typ(Email(""))), "string")
+}
+
+func TestAliasNamedPrimitives(t *testing.T) {
+	type Address = string
+	type Contact struct {
+		Email   Email
+		Address Address
+		Active  Flag
+	}
+	c := NewConverter()
+	c.AliasNamedPrimitives = true
+	expect(t, c.Convert(typ(Email(""))), "Email")
+	expect(t, c.Convert(typ(Address(""))), "string")
+	expect(t, c.Convert(typ(Contact{})), "{ Email: Email, Address: string, Active: Flag }")
+	expect(t, c.Convert(typ(json.Number(""))), "number | string")
+	expect(t, c.File(), "export type Email = string\n\nexport type Flag = boolean\n")
+}
+
+func TestJSONTags(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(struct {
+		Name string `json:"name"`
+	}{})), "{ name: string }")
+	expect(t, c.Convert(typ(struct {
+		Name string `json:"-"`
+		Age  int
+	}{})), "{ Age: number }")
+	expect(t, c.Convert(typ(struct {
+		Name string `json:"name,omitempty"`
+	}{})), "{ name?: string }")
+	expect(t, c.Convert(typ(struct {
+		Name string `json:",omitempty"`
+	}{})), "{ Name?: string }")
+	expect(t, c.Convert(typ(struct {
+		Name string `json:""`
+	}{})), "{ Name: string }")
+}
+
+func TestBytes(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(byte(0))), "number")
+	expect(t, c.Convert(typ([]byte{})), "string")
+	expect(t, c.Convert(typ([8]byte{})), "Array<number>")
+	expect(t, c.Convert(typ(struct {
+		B   byte
+		Bs  []byte
+		B8  [8]byte
+		Bss [][]byte
+	}{})), "{ B: number, Bs: string, B8: Array<number>, Bss: Array<string> }")
+}
+
+func TestUnionTypes(t *testing.T) {
+	type Ref struct{ ID string }
+	c := NewConverter()
+	c.AddTypes(map[reflect.Type]string{typ(Ref{}): "string | { id: string }"})
+	expect(t, c.Convert(typ([]Ref{})), "Array<string | { id: string }>")
+	expect(t, c.Convert(typ(map[string]Ref{})), "{ [k: string]: string | { id: string } }")
+	expect(t, c.Convert(typ(struct{ Ref Ref }{})), "{ Ref: string | { id: string } }")
+	expect(t, c.Convert(typ(func(Ref) Ref { return Ref{} })), "(ref: string | { id: string }) => Promise<string | { id: string }>")
+	c.IntKeyMapsAsArrays = true
+	c.SparseArrays = true
+	expect(t, c.Convert(typ(map[int]Ref{})), "Array<string | { id: string } | undefined>")
+	expect(t, c.Convert(typ(map[int]func(){})), "Array<(() => Promise<void>) | undefined>")
+}
+
+func TestDeclarations(t *testing.T) {
+	c := NewConverter()
+	c.Declare(typ(Nested{}), typ(&User{}))
+	expect(t, c.Convert(typ(Nested{})), "Nested")
+	expect(t, c.Convert(typ([]*User{})), "Array<User>")
+	expect(t, c.File(), "export interface Nested { Owner: User }\n\nexport interface User { Name: string }\n")
+	c.SortDeclarations = true
+	expect(t, c.File(), "export interface User { Name: string }\n\nexport interface Nested { Owner: User }\n")
+}
+
+func TestStructStyles(t *testing.T) {
+	c := NewConverter()
+	c.Declare(typ(User{}))
+	expect(t, c.File(), "export interface User { Name: string }\n")
+	c.StructStyle = StructStyleTypeAlias
+	expect(t, c.File(), "export type User = { Name: string }\n")
+	expect(t, c.Convert(typ(User{})), "User")
+}
+
+func TestDeclarationsEmpty(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.File(), "")
+}
+
+func TestDeclarationsByName(t *testing.T) {
+	c := NewConverter()
+	c.DeclareStructs = true
+	c.BrandedNumbers = true
+	c.Convert(typ(Nested{}))
+	c.Convert(typ(struct{ Count int }{}))
+	c.AddConstGroup("Limits", []Const{{"Max", 3}})
+	decls := c.Declarations()
+	if len(decls) != 4 {
+		t.Fatalf("expected 4 declarations but got %d", len(decls))
+	}
+	expect(t, decls["Nested"], "export interface Nested { Owner: User }")
+	expect(t, decls["User"], "export interface User { Name: string }")
+	expect(t, decls["Int"], "export type Int = number & { __int: void }")
+	expect(t, decls["Limits"], "export const Limits = { Max: 3 } as const")
+}
+
+func TestAddTypesWithImport(t *testing.T) {
+	type Account struct {
+		Owner User
+		Email Email
+	}
+	c := NewConverter()
+	c.AddTypesWithImport(map[reflect.Type]ImportedType{
+		typ(User{}):      {Name: "User", From: "./user"},
+		typ(Email("")):   {Name: "Email", From: "./email"},
+		typ(Flag(false)): {Name: "Flag", From: "./user"},
+	})
+	expect(t, c.Convert(typ(Flag(false))), "Flag")
+	c.Declare(typ(Account{}))
+	expect(t, c.File(), "import type { Email } from \"./email\"\nimport type { User } from \"./user\"\n\nexport interface Account { Owner: User, Email: Email }\n")
+}
+
+type Animal interface{ Sound() string }
+type Dog struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+type Cat struct {
+	Type  string `json:"type"`
+	Lives int    `json:"lives"`
+}
+
+func (Dog) Sound() string { return "woof" }
+func (Cat) Sound() string { return "meow" }
+
+func TestUnions(t *testing.T) {
+	c := NewConverter()
+	c.AddUnion(typ((*Animal)(nil)), typ(Dog{}), typ(Cat{}))
+	expect(t, c.Convert(typ(struct{ Pets []Animal }{})), "{ Pets: Array<Animal> }")
+	expect(t, c.File(), "export type Animal = Dog | Cat\n\nexport interface Dog { type: string, name: string }\n\nexport interface Cat { type: string, lives: number }\n")
+	c.EmitTypeGuards = true
+	c.SortDeclarations = true
+	expect(t, c.File(), `export interface Dog { type: string, name: string }
+
+export interface Cat { type: string, lives: number }
+
+export type Animal = Dog | Cat
+export function isDog(x: Animal): x is Dog { return x.type === "Dog" }
+export function isCat(x: Animal): x is Cat { return x.type === "Cat" }
+`)
+}
+
+func TestUnionPointerMembers(t *testing.T) {
+	c := NewConverter()
+	c.PointerMode = PointerModeNull
+	c.EmitTypeGuards = true
+	c.AddUnion(typ((*Animal)(nil)), typ(&Dog{}), typ(Cat{}))
+	expect(t, c.File(), `export type Animal = Dog | Cat
+export function isDog(x: Animal): x is Dog { return x.type === "Dog" }
+export function isCat(x: Animal): x is Cat { return x.type === "Cat" }
+
+export interface Dog { type: string, name: string }
+
+export interface Cat { type: string, lives: number }
+`)
+}
+
+func TestAllDeclarations(t *testing.T) {
+	c := NewConverter()
+	c.AddTypes(map[reflect.Type]string{
+		typ(User{}):      "User",
+		typ(Nested{}):    "Owned",
+		typ(Money{}):     "string",
+		typ(Flag(false)): "boolean | null",
+		typ(Server{}):    "Uint8Array",
+	})
+	c.Declare(typ(Options{}))
+	expect(t, c.AllDeclarations(), "export interface Options { Name: string, Age: number, Email: string, Admin: boolean, Tags: Array<string>, Metadata: { [k: string]: string } }\n\nexport interface User { Name: string }\n")
+}
+
+func TestDeclarationIndex(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.DeclarationIndex("Schema"), "export interface Schema {}")
+	c.DeclareStructs = true
+	c.Declare(typ(Nested{}))
+	expect(t, c.DeclarationIndex("Schema"), "export interface Schema { Nested: Nested, User: User }")
+}
+
+func TestInlineUnions(t *testing.T) {
+	c := NewConverter()
+	c.AddUnion(typ((*Animal)(nil)), typ(Dog{}), typ(Cat{}))
+	expect(t, c.Convert(typ(func(Animal) {})), "(animal: Animal) => Promise<void>")
+	c.InlineUnions = true
+	expect(t, c.Convert(typ(func(Animal) {})), "(animal: Dog | Cat) => Promise<void>")
+	expect(t, c.Convert(typ(func(Animal, Animal) Animal { return nil })), "(animal: Dog | Cat, animal1: Dog | Cat) => Promise<Dog | Cat>")
+	expect(t, c.File(), "export type Animal = Dog | Cat\n\nexport interface Dog { type: string, name: string }\n\nexport interface Cat { type: string, lives: number }\n")
+}
+
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func (m Money) MarshalJSON() ([]byte, error) { return json.Marshal(m.Currency) }
+
+type Temperature float64
+
+func (*Temperature) MarshalJSON() ([]byte, error) { return []byte("0"), nil }
+
+func TestMarshalers(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(Money{})), "unknown")
+	expect(t, c.Convert(typ(struct {
+		Price Money        `json:"price"`
+		Temp  *Temperature `json:"temp"`
+	}{})), "{ price: unknown, temp: unknown }")
+	expect(t, c.ConvertZod(typ(struct{ Price Money }{})), "z.object({ Price: z.unknown() })")
+	expectSchema(t, c, struct{ Price Money }{}, `{"properties":{"Price":{}},"required":["Price"],"type":"object"}`)
+
+	c.DeclareStructs = true
+	c.NameStructParams = true
+	expect(t, c.Convert(typ(func(Money) {})), "(money: unknown) => Promise<void>")
+	expect(t, c.Convert(typ(struct{ Price Money }{})), "{ Price: unknown }")
+	expect(t, c.File(), "")
+	c.Declare(typ(Money{}))
+	expect(t, c.File(), "export type Money = unknown\n")
+
+	c.AddTypes(map[reflect.Type]string{typ(Money{}): "string"})
+	expect(t, c.Convert(typ([]Money{})), "Array<string>")
+}
+
+func TestTime(t *testing.T) {
+	type Event struct {
+		Start time.Time  `json:"start"`
+		End   *time.Time `json:"end"`
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(time.Time{})), "string")
+	expect(t, c.Convert(typ(Event{})), "{ start: string, end: string }")
+	c.PointerMode = PointerModeOptional
+	expect(t, c.Convert(typ(Event{})), "{ start: string, end?: string }")
+	c.PointerMode = PointerModeNull
+	expect(t, c.Convert(typ(Event{})), "{ start: string, end: string | null }")
+}
+
+func TestTimeType(t *testing.T) {
+	type Event struct {
+		Start   time.Time `json:"start"`
+		Created time.Time `json:"created" ts:"Date"`
+		Ends    []time.Time
+		Updated *time.Time  `json:"updated" ts:"Date"`
+		Times   []time.Time `json:"times" ts:"Date"`
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(Event{})), "{ start: string, created: Date, Ends: Array<string>, updated: Date, times: Array<Date> }")
+	c.TimeType = "Date"
+	expect(t, c.Convert(typ(Event{})), "{ start: Date, created: Date, Ends: Array<Date>, updated: Date, times: Array<Date> }")
+	c.TimeType = "number"
+	expect(t, c.Convert(typ(Event{})), "{ start: number, created: Date, Ends: Array<number>, updated: Date, times: Array<Date> }")
+	c.PointerMode = PointerModeNull
+	expect(t, c.Convert(typ(Event{})), "{ start: number, created: Date, Ends: Array<number>, updated: Date | null, times: Array<Date> }")
+
+	// the override does not apply to nested structs or other fields
+	type Wrapper struct{ At time.Time }
+	type Meta struct {
+		Meta  Wrapper   `ts:"Date"`
+		Other time.Time `ts:"Date"`
+		Later time.Time
+		Name  string `ts:"Date"`
+	}
+	c = NewConverter()
+	c.OnConvert = func(t reflect.Type, ts string) {
+		if t == timeType {
+			c.AddTypes(map[reflect.Type]string{t: ts})
+		}
+	}
+	expect(t, c.Convert(typ(Meta{})), "{ Meta: { At: string }, Other: Date, Later: string, Name: string }")
+}
+
+func TestJSONNumber(t *testing.T) {
+	type Price struct {
+		Amount json.Number `json:"amount"`
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(Price{})), "{ amount: number | string }")
+	expect(t, c.Convert(typ([]json.Number{})), "Array<number | string>")
+	expect(t, c.Convert(typ(map[string]json.Number{})), "{ [k: string]: number | string }")
+	expect(t, c.ConvertZod(typ(Price{})), "z.object({ amount: z.union([z.number(), z.string()]) })")
+	expectSchema(t, c, Price{}, `{"properties":{"amount":{"type":["number","string"]}},"required":["amount"],"type":"object"}`)
+	c.BrandedNumbers = true
+	expect(t, c.Convert(typ(Price{})), "{ amount: number | string }")
+}
+
+func TestPointers(t *testing.T) {
+	c := NewConverter()
+	c.PointerMode = PointerModeOptional
+	expect(t, c.Convert(typ(&User{})), "{ Name: string }")
+	expect(t, c.Convert(typ(struct{ Owner *User }{})), "{ Owner?: { Name: string } }")
+	c.PointerMode = PointerModeNull
+	expect(t, c.Convert(typ(&User{})), "{ Name: string } | null")
+	u := &User{}
+	expect(t, c.Convert(typ(&u)), "{ Name: string } | null")
+	expect(t, c.Convert(typ(struct{ Owner *User }{})), "{ Owner: { Name: string } | null }")
+	expect(t, c.Convert(typ(func() *User { return nil })), "() => Promise<{ Name: string } | null>")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{IsSync: true} }
+	expect(t, c.Convert(typ(func() *User { return nil })), "() => { Name: string } | null")
+	expect(t, c.Convert(typ(func() (*User, error) { return nil, nil })), "() => { Name: string } | null")
+	expect(t, c.Convert(typ(struct{ Load func() *User }{})), "{ Load: () => { Name: string } | null }")
+}
+
+func TestOptionalNullable(t *testing.T) {
+	type Fields struct {
+		A string  `json:"a"`
+		B string  `json:"b,omitempty"`
+		C *string `json:"c"`
+		D *string `json:"d,omitempty"`
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(Fields{})), "{ a: string, b?: string, c: string, d?: string }")
+	c.PointerMode = PointerModeOptional
+	expect(t, c.Convert(typ(Fields{})), "{ a: string, b?: string, c?: string, d?: string }")
+	c.PointerMode = PointerModeNull
+	expect(t, c.Convert(typ(Fields{})), "{ a: string, b?: string, c: string | null, d?: string | null }")
+}
+
+func TestFuncsNonNull(t *testing.T) {
+	c := NewConverter()
+	c.PointerMode = PointerModeNull
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{NonNull: true} }
+	expect(t, c.Convert(typ(func() (*User, error) { return nil, nil })), "() => Promise<{ Name: string }>")
+	expect(t, c.Convert(typ(func() (*User, *int) { return nil, nil })), "() => Promise<[{ Name: string }, number]>")
+	expect(t, c.Convert(typ(func() []*User { return nil })), "() => Promise<Array<{ Name: string } | null>>")
+	expect(t, c.Convert(typ(func(*User) {})), "(user: { Name: string } | null) => Promise<void>")
+	c.AddTypes(map[reflect.Type]string{typ(Email("")): "string | null"})
+	expect(t, c.Convert(typ(func() Email { return "" })), "() => Promise<string | null>")
+}
+
+func TestNestedPointers(t *testing.T) {
+	c := NewConverter()
+	c.PointerMode = PointerModeNull
+	expect(t, c.Convert(typ(map[string][]*User{})), "{ [k: string]: Array<{ Name: string } | null> }")
+	expect(t, c.Convert(typ(struct {
+		Teams map[string][]*User `json:"teams"`
+	}{})), "{ teams: { [k: string]: Array<{ Name: string } | null> } }")
+	expect(t, c.Convert(typ(map[string]*[]*User{})), "{ [k: string]: Array<{ Name: string } | null> | null }")
+	c.RecordMaps = true
+	expect(t, c.Convert(typ(map[string][]*User{})), "Record<string, Array<{ Name: string } | null>>")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{IsSync: true} }
+	load := func() *User { return nil }
+	expect(t, c.Convert(typ(&load)), "(() => { Name: string } | null) | null")
+}
+
+func TestTypeNamer(t *testing.T) {
+	c := NewConverter()
+	c.TypeNamer = func(t reflect.Type) string {
+		return strings.ReplaceAll(t.PkgPath(), "/", "_") + "_" + t.Name()
+	}
+	c.Declare(typ(http.Header{}), typ(mail.Header{}))
+	expect(t, c.Convert(typ(http.Header{})), "net_http_Header")
+	expect(t, c.Convert(typ(mail.Header{})), "net_mail_Header")
+	expect(t, c.File(), "export type net_http_Header = { [k: string]: Array<string> }\n\nexport type net_mail_Header = { [k: string]: Array<string> }\n")
+	c.NamedBoolMode = BoolModeBranded
+	expect(t, c.Convert(typ(Flag(false))), "boolean & { __brand: \"github.com_alanshaw_go2ts_Flag\" }")
+}
+
+func TestFuncsBlankIdentifiers(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(func() (_ string, _ error) { return })), "() => Promise<string>")
+	expect(t, c.Convert(typ(func() (_ string, _ int, _ error) { return })), "() => Promise<[string, number]>")
+	expect(t, c.Convert(typ(func(_ string, _ int) {})), "(str: string, int: number) => Promise<void>")
+	expect(t, c.Convert(typ(func(_, _ string) {})), "(str: string, str1: string) => Promise<void>")
+	expect(t, c.Convert(typ(func(_ context.Context, _ User) (_ error) { return })), "(user: { Name: string }) => Promise<void>")
+	// only a trailing error is ignored
+	expect(t, c.Convert(typ(func() (_ error, _ string) { return })), "() => Promise<[any, string]>")
+	expect(t, c.Convert(typ(func() (_ error, _ error) { return })), "() => Promise<any>")
+}
+
+func TestFuncsReturnAsObject(t *testing.T) {
+	c := NewConverter()
+	c.ConfigureFunc = func(t reflect.Type) FuncConf {
+		return FuncConf{ReturnAsObject: true, ReturnNames: []string{"user", "count"}}
+	}
+	expect(t, c.Convert(typ(func() (User, int, error) { return User{}, 0, nil })), "() => Promise<{ user: { Name: string }, count: number }>")
+	expect(t, c.Convert(typ(func() User { return User{} })), "() => Promise<{ Name: string }>")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{ReturnAsObject: true} }
+	expect(t, c.Convert(typ(func() (string, int) { return "", 0 })), "() => Promise<{ 0: string, 1: number }>")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf {
+		return FuncConf{ReturnAsObject: true, AlwaysArray: true, ReturnNames: []string{"name"}}
+	}
+	expect(t, c.Convert(typ(func() string { return "" })), "() => Promise<{ name: string }>")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf {
+		return FuncConf{ReturnAsObject: true, ReturnNames: []string{"my-name"}}
+	}
+	expect(t, c.Convert(typ(func() (string, int) { return "", 0 })), "() => Promise<{ \"my-name\": string, 1: number }>")
+}
+
+type base struct{ ID string }
+type Recursive struct{ *Recursive }
+
+func TestEmbedded(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(struct {
+		io.Reader
+		Name string
+	}{})), "{ Reader: any, Name: string }")
+	expect(t, c.Convert(typ(struct {
+		User
+		Age int
+	}{})), "{ Name: string, Age: number }")
+	expect(t, c.Convert(typ(struct {
+		*User
+		base
+	}{})), "{ Name: string, ID: string }")
+	expect(t, c.Convert(typ(struct {
+		User `json:"user"`
+	}{})), "{ user: { Name: string } }")
+	expect(t, c.Convert(typ(struct {
+		io.Reader `json:"-"`
+	}{})), "{}")
+	// the MarshalJSON method of time.Time is promoted
+	expect(t, c.Convert(typ(struct{ time.Time }{})), "unknown")
+	expect(t, c.Convert(typ(Recursive{})), "{}")
+}
+
+func TestEmbeddedUnexportedFields(t *testing.T) {
+	type hidden struct{ id, secret string }
+	type Item struct {
+		hidden
+		*Recursive
+		Name string `json:"name"`
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(Item{})), "{ name: string }")
+	expect(t, c.Convert(typ(struct{ hidden }{})), "{}")
+	c.Indent = "  "
+	expect(t, c.Convert(typ(Item{})), "{\n  name: string\n}")
+}
+
+func TestEmbeddedMapsSlices(t *testing.T) {
+	type tags map[string]string
+	type Item struct {
+		Headers
+		Tags
+		tags
+		Name string
+	}
+	c := NewConverter()
+	// encoded as fields named after the type, like embedded interfaces
+	expect(t, c.Convert(typ(Item{})), "{ Headers: { [k: string]: string }, Tags: Array<string>, Name: string }")
+	c.AliasNamedTypes = true
+	expect(t, c.Convert(typ(Item{})), "{ Headers: Headers, Tags: Tags, Name: string }")
+}
+
+type Tree struct {
+	Kids map[string]*Tree
+}
+
+func TestRecursion(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(Tree{})), "Tree")
+	expect(t, c.Convert(typ(struct{ Root *Tree }{})), "{ Root: Tree }")
+	expect(t, c.File(), "export interface Tree { Kids: { [k: string]: Tree } }\n")
+}
+
+func TestOpaqueStructs(t *testing.T) {
+	type opaque struct{ id string }
+	c := NewConverter()
+	expect(t, c.Convert(typ(opaque{})), "{}")
+	expect(t, c.Convert(typ(struct{}{})), "{}")
+	c.UnknownOpaqueStructs = true
+	expect(t, c.Convert(typ(opaque{})), "Record<string, unknown>")
+	expect(t, c.Convert(typ(struct{}{})), "{}")
+	expect(t, c.Convert(typ(struct {
+		Name string `json:"-"`
+	}{})), "{}")
+	expect(t, c.Convert(typ(struct{ Inner opaque }{})), "{ Inner: Record<string, unknown> }")
+}
+
+func TestBoolKeyMaps(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(map[bool]int{})), "{ [k: string]: number }")
+	c.BoolKeyLiterals = true
+	expect(t, c.Convert(typ(map[bool]int{})), "Record<\"true\" | \"false\", number>")
+	expect(t, c.Convert(typ(map[Flag]string{})), "Record<\"true\" | \"false\", string>")
+	expect(t, c.Convert(typ(map[string]int{})), "{ [k: string]: number }")
+}
+
+type Future struct{}
+
+func TestFlattenPromises(t *testing.T) {
+	c := NewConverter()
+	c.AddTypes(map[reflect.Type]string{typ(Future{}): "Promise<string>"})
+	expect(t, c.Convert(typ(func() Future { return Future{} })), "() => Promise<Promise<string>>")
+	expect(t, c.Convert(typ(func() chan func() string { return nil })), "() => Promise<AsyncIterable<() => Promise<string>>>")
+	c.FlattenPromises = true
+	expect(t, c.Convert(typ(func() Future { return Future{} })), "() => Promise<string>")
+	expect(t, c.Convert(typ(func() (Future, error) { return Future{}, nil })), "() => Promise<string>")
+	expect(t, c.Convert(typ(func() (Future, Future) { return Future{}, Future{} })), "() => Promise<[Promise<string>, Promise<string>]>")
+	expect(t, c.Convert(typ(func() func() Future { return nil })), "() => Promise<() => Promise<string>>")
+	expect(t, c.Convert(typ(func() chan func() string { return nil })), "() => Promise<AsyncIterable<() => Promise<string>>>")
+}
+
+func TestJSONTagsString(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(struct {
+		Count int `json:"n,omitempty,string"`
+	}{})), "{ n?: string }")
+	expect(t, c.Convert(typ(struct {
+		Count int  `json:",string"`
+		On    bool `json:"on,string,omitempty"`
+	}{})), "{ Count: string, on?: string }")
+	// only applies to scalars
+	expect(t, c.Convert(typ(struct {
+		Counts []int `json:"counts,string"`
+	}{})), "{ counts: Array<number> }")
+	c.PointerMode = PointerModeNull
+	expect(t, c.Convert(typ(struct {
+		Count *int `json:"n,string"`
+	}{})), "{ n: string | null }")
+}
+
+type Options struct {
+	Name     string
+	Age      int
+	Email    string
+	Admin    bool
+	Tags     []string
+	Metadata map[string]string
+}
+
+func TestNameStructParams(t *testing.T) {
+	c := NewConverter()
+	c.NameStructParams = true
+	expect(t, c.Convert(typ(func(Options) {})), "(options: Options) => Promise<void>")
+	expect(t, c.Convert(typ(func(*User, struct{ ID string }) {})), "(user: User, _: { ID: string }) => Promise<void>")
+	expect(t, c.Convert(typ(func(time.Time) {})), "(time: string) => Promise<void>")
+	expect(t, c.File(), "export interface Options { Name: string, Age: number, Email: string, Admin: boolean, Tags: Array<string>, Metadata: { [k: string]: string } }\n\nexport interface User { Name: string }\n")
+}
+
+func TestHoistStructParams(t *testing.T) {
+	type Filter = struct {
+		Name  string `json:"name"`
+		Limit int    `json:"limit"`
+		Skip  int    `json:"skip"`
+	}
+	c := NewConverter()
+	c.HoistStructParams = 3
+	c.ConfigureFunc = func(t reflect.Type) FuncConf {
+		return FuncConf{IsMethod: true, MethodName: "list", ParamNames: []string{"filter", "page"}}
+	}
+	expect(t, c.Convert(typ(func(Service, Filter, struct{ Size int }) {})), "list (filter: ListFilter, page: { Size: number }): Promise<void>")
+	expect(t, c.Convert(typ(func(Service, *Filter) {})), "list (filter: ListFilter): Promise<void>")
+	expect(t, c.File(), "export interface ListFilter { name: string, limit: number, skip: number }\n")
+
+	// names used by other types are made unique
+	c.Declare(typ(Options{}))
+	c.ConfigureFunc = func(t reflect.Type) FuncConf {
+		return FuncConf{ParamNames: []string{"options"}}
+	}
+	expect(t, c.Convert(typ(func(struct{ A, B, C string }) {})), "(options: Options1) => Promise<void>")
+	expect(t, c.Convert(typ(func(struct{ X, Y, Z int }) {})), "(options: Options2) => Promise<void>")
+	expect(t, c.Convert(typ(func(struct{ A, B, C string }) {})), "(options: Options1) => Promise<void>")
+	expect(t, c.File(), "export interface ListFilter { name: string, limit: number, skip: number }\n\nexport interface Options { Name: string, Age: number, Email: string, Admin: boolean, Tags: Array<string>, Metadata: { [k: string]: string } }\n\nexport interface Options1 { A: string, B: string, C: string }\n\nexport interface Options2 { X: number, Y: number, Z: number }\n")
+
+	// unnamed params are not hoisted
+	c = NewConverter()
+	c.HoistStructParams = 3
+	expect(t, c.Convert(typ(func(Filter) {})), "(_: { name: string, limit: number, skip: number }) => Promise<void>")
+	expect(t, c.File(), "")
+}
+
+func TestDeclareStructs(t *testing.T) {
+	c := NewConverter()
+	c.DeclareStructs = true
+	expect(t, c.Convert(typ(Nested{})), "Nested")
+	expect(t, c.Convert(typ(&User{})), "User")
+	expect(t, c.Convert(typ(struct{ Users []User }{})), "{ Users: Array<User> }")
+	expect(t, c.Convert(typ(func(Nested) time.Time { return time.Time{} })), "(nested: Nested) => Promise<string>")
+	expect(t, c.File(), "export interface Nested { Owner: User }\n\nexport interface User { Name: string }\n")
+}
+
+type Box[T any] struct{ Value T }
+type GenericTree[T any] struct {
+	Value    T
+	Children []*GenericTree[T]
+}
+
+func TestGenericNames(t *testing.T) {
+	type Order struct{ Items []Box[User] }
+	c := NewConverter()
+	expect(t, c.Convert(typ(GenericTree[int]{})), "GenericTreeInt")
+	expect(t, c.Convert(typ(func(Box[string]) {})), "(boxString: { Value: string }) => Promise<void>")
+	c.DeclareStructs = true
+	expect(t, c.Convert(typ(Order{})), "Order")
+	expect(t, c.File(), "export interface GenericTreeInt { Value: number, Children: Array<GenericTreeInt> }\n\nexport interface Order { Items: Array<BoxUser> }\n\nexport interface BoxUser { Value: User }\n\nexport interface User { Name: string }\n")
+	for name, d := range c.Declarations() {
+		if err := Validate(strings.TrimPrefix(d, "export interface "+name+" ")); err != nil || !isIdent(name) {
+			t.Fatalf("invalid declaration %q: %v", d, err)
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	type Handle struct{ Ptr uintptr }
+	c := NewConverter()
+	ts, err := c.TryConvert(typ(Handle{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, ts, "{ Ptr: number }")
+	_, err = c.TryConvert(typ(unsafe.Pointer(nil)))
+	if err == nil {
+		t.Fatal("expected error converting unsafe.Pointer")
+	}
+	c.Strict = true
+	_, err = c.TryConvert(typ(Handle{}))
+	if err == nil {
+		t.Fatal("expected error converting uintptr in strict mode")
+	}
+	_, err = c.TryConvert(typ(func(uintptr) {}))
+	if err == nil {
+		t.Fatal("expected error converting uintptr param in strict mode")
+	}
+	ts, err = c.TryConvert(typ(User{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, ts, "{ Name: string }")
+
+	// bugs e.g. in ConfigureFunc are not returned as conversion errors
+	defer func() {
+		if _, ok := recover().(runtime.Error); !ok {
+			t.Fatal("expected runtime error panic")
+		}
+	}()
+	c.ConfigureFunc = func(t reflect.Type) FuncConf {
+		var m map[string]bool
+		m[t.String()] = true
+		return FuncConf{}
+	}
+	c.TryConvert(typ(func() {}))
+}
+
+func TestBrandedNumbers(t *testing.T) {
+	type Measurement struct {
+		Count int     `json:"count"`
+		Value float64 `json:"value"`
+	}
+	type Score int64
+	c := NewConverter()
+	c.BrandedNumbers = true
+	expect(t, c.Convert(typ(0)), "Int")
+	expect(t, c.Convert(typ(Score(0))), "Int")
+	expect(t, c.Convert(typ("")), "string")
+	c.Declare(typ(Measurement{}))
+	expect(t, c.File(), "export type Int = number & { __int: void }\n\nexport type Float = number & { __float: void }\n\nexport interface Measurement { count: Int, value: Float }\n")
+}
+
+type Service struct{}
+
+func (Service) Get(id string) (User, error)            { return User{}, nil }
+func (Service) Close() error                           { return nil }
+func (*Service) Set(ctx context.Context, u User) error { return nil }
+
+type Getter interface {
+	Get(id string) (User, error)
+}
+
+func TestConvertMethodSet(t *testing.T) {
+	c := NewConverter()
+	c.AddTypes(map[reflect.Type]string{typ(User{}): "User"})
+	expect(t, c.ConvertMethodSet(typ(&Service{})), "export declare class Service {\n  Close (): Promise<void>\n  Get (str: string): Promise<User>\n  Set (user: User): Promise<void>\n}")
+	expect(t, c.ConvertMethodSet(typ(Service{})), "export declare class Service {\n  Close (): Promise<void>\n  Get (str: string): Promise<User>\n}")
+	expect(t, c.ConvertMethodSet(typ((*Getter)(nil)).Elem()), "export declare class Getter {\n  Get (str: string): Promise<User>\n}")
+	expect(t, c.ConvertMethodSet(typ(User{})), "export declare class User {}")
+	// methods are output once per name
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{MethodName: "call", IsSync: true} }
+	expect(t, c.ConvertMethodSet(typ(&Service{})), "export declare class Service {\n  call (): void\n}")
+}
+
+func TestAbstractClasses(t *testing.T) {
+	c := NewConverter()
+	c.AddTypes(map[reflect.Type]string{typ(User{}): "User"})
+	c.AbstractClasses = true
+	expect(t, c.ConvertMethodSet(typ(Service{})), "export abstract class Service {\n  abstract Close (): Promise<void>\n  abstract Get (str: string): Promise<User>\n}")
+	expect(t, c.ConvertMethodSet(typ(User{})), "export abstract class User {}")
+}
+
+func TestSortMethods(t *testing.T) {
+	c := NewConverter()
+	c.AddTypes(map[reflect.Type]string{typ(User{}): "User"})
+	// name methods by number of params: Close, Get, Set
+	names := []string{"shutdown", "get", "set"}
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{MethodName: names[t.NumIn()-1]} }
+	expect(t, c.ConvertMethodSet(typ(&Service{})), "export declare class Service {\n  shutdown (): Promise<void>\n  get (str: string): Promise<User>\n  set (user: User): Promise<void>\n}")
+	c.SortMethods = true
+	expect(t, c.ConvertMethodSet(typ(&Service{})), "export declare class Service {\n  get (str: string): Promise<User>\n  set (user: User): Promise<void>\n  shutdown (): Promise<void>\n}")
+}
+
+func TestAllFieldsOptional(t *testing.T) {
+	type Patch struct {
+		ID    string `json:"id" validate:"required"`
+		Name  string `json:"name"`
+		Email string `json:"email" validate:"required,email"`
+		Age   int    `json:"age" validate:"min=0"`
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(Patch{})), "{ id: string, name: string, email: string, age: number }")
+	c.AllFieldsOptional = true
+	expect(t, c.Convert(typ(Patch{})), "{ id: string, name?: string, email: string, age?: number }")
+}
+
+type Headers map[string]string
+type Tags []string
+
+func TestAliasNamedTypes(t *testing.T) {
+	type Request struct {
+		Headers Headers `json:"headers"`
+		Tags    Tags    `json:"tags"`
+		Query   map[string]string
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(Request{})), "{ headers: { [k: string]: string }, tags: Array<string>, Query: { [k: string]: string } }")
+	c.AliasNamedTypes = true
+	expect(t, c.Convert(typ(Request{})), "{ headers: Headers, tags: Tags, Query: { [k: string]: string } }")
+	expect(t, c.File(), "export type Headers = { [k: string]: string }\n\nexport type Tags = Array<string>\n")
+}
+
+func TestMultiLine(t *testing.T) {
+	type Account struct {
+		Owner    User   `json:"owner"`
+		OldField string `json:"oldField" deprecated:"use newField"`
+		NewField string `json:"newField"`
+		Legacy   bool   `json:"legacy" deprecated:""`
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(Account{})), "{ owner: { Name: string }, oldField: string, newField: string, legacy: boolean }")
+	c.Indent = "  "
+	expect(t, c.Convert(typ(Account{})), `{
+  owner: {
+    Name: string
+  },
+  /** @deprecated use newField */
+  oldField: string,
+  newField: string,
+  /** @deprecated */
+  legacy: boolean
+}`)
+	if err := Validate(c.Convert(typ(Account{}))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStrictOptional(t *testing.T) {
+	type Profile struct {
+		Name  string  `json:"name,omitempty"`
+		Bio   *string `json:"bio,omitempty"`
+		Email string  `json:"email"`
+	}
+	c := NewConverter()
+	c.StrictOptional = true
+	expect(t, c.Convert(typ(Profile{})), "{ name?: string | undefined, bio?: string | undefined, email: string }")
+	expect(t, c.Convert(typ(struct {
+		Tags  map[string]string `json:"tags,omitempty"`
+		Slots map[int]string    `json:"slots"`
+	}{})), "{ tags?: { [k: string]: string | undefined } | undefined, slots: { [k: string]: string | undefined } }")
+	c.IntKeyMapsAsArrays = true
+	c.RecordMaps = true
+	expect(t, c.Convert(typ(map[int]string{})), "Array<string | undefined>")
+	expect(t, c.Convert(typ(map[string]int{})), "Partial<Record<string, number>>")
+	c.IntKeyMapsAsArrays = false
+	c.RecordMaps = false
+	c.PointerMode = PointerModeNull
+	expect(t, c.Convert(typ(Profile{})), "{ name?: string | undefined, bio?: string | null | undefined, email: string }")
+	c.Indent = "  "
+	if err := Validate(c.Convert(typ(Profile{}))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidatorBounds(t *testing.T) {
+	type Score struct {
+		Value int     `json:"value" validate:"required,min=0,max=100"`
+		Label string  `json:"label" validate:"min=1"`
+		Ratio float64 `binding:"max=1"`
+	}
+	c := NewConverter()
+	c.Indent = "  "
+	expect(t, c.Convert(typ(Score{})), `{
+  /**
+   * @minimum 0
+   * @maximum 100
+   */
+  value: number,
+  label: string,
+  Ratio: number
+}`)
+	c.ValidatorTag = "binding"
+	expect(t, c.Convert(typ(Score{})), `{
+  value: number,
+  label: string,
+  /** @maximum 1 */
+  Ratio: number
+}`)
+}
+
+func TestReadonly(t *testing.T) {
+	type Record struct {
+		ID   string `json:"id" ts:",readonly"`
+		Name string `json:"name"`
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(Record{})), "{ readonly id: string, name: string }")
+	c.Readonly = true
+	expect(t, c.Convert(typ(Record{})), "{ readonly id: string, readonly name: string }")
+	expect(t, c.Convert(typ(func() (string, int, error) { return "", 0, nil })), "() => Promise<readonly [string, number]>")
+	expect(t, c.Convert(typ(func() string { return "" })), "() => Promise<string>")
+	if err := Validate(c.Convert(typ(func() (string, int) { return "", 0 }))); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(c.Convert(typ(Record{}))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDescriptionTag(t *testing.T) {
+	type Account struct {
+		ID    string `json:"id" description:"Unique account ID."`
+		Email string `json:"email" description:"Contact address." deprecated:"use contacts"`
+	}
+	c := NewConverter()
+	c.Indent = "  "
+	c.DescriptionTag = "description"
+	expect(t, c.Convert(typ(Account{})), `{
+  /** Unique account ID. */
+  id: string,
+  /**
+   * Contact address.
+   * @deprecated use contacts
+   */
+  email: string
+}`)
+	if err := Validate(c.Convert(typ(Account{}))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type Context struct{ Name string }
+type RequestContext interface {
+	context.Context
+	RequestID() string
+}
+
+func TestFuncsContext(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(func(string, context.Context) {})), "(str: string) => Promise<void>")
+	expect(t, c.Convert(typ(func(context.Context, string, context.Context, int) {})), "(str: string, int: number) => Promise<void>")
+	expect(t, c.Convert(typ(func(RequestContext, string) {})), "(str: string) => Promise<void>")
+	// not a context.Context
+	expect(t, c.Convert(typ(func(Context) {})), "(context: { Name: string }) => Promise<void>")
+	expect(t, c.Convert(typ(func(interface{}) {})), "(_: any) => Promise<void>")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{OnlyIgnoreFirstContext: true} }
+	expect(t, c.Convert(typ(func(context.Context, string) {})), "(str: string) => Promise<void>")
+	expect(t, c.Convert(typ(func(string, context.Context) {})), "(str: string, context: any) => Promise<void>")
+}
+
+func TestFuncsNullableReturns(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(func() *User { return nil })), "() => Promise<{ Name: string }>")
+	c.PointerMode = PointerModeNull
+	expect(t, c.Convert(typ(func() *User { return nil })), "() => Promise<{ Name: string } | null>")
+	expect(t, c.Convert(typ(func() (*User, error) { return nil, nil })), "() => Promise<{ Name: string } | null>")
+	expect(t, c.Convert(typ(func() (*User, int) { return nil, 0 })), "() => Promise<[{ Name: string } | null, number]>")
+	expect(t, c.Convert(typ(func() User { return User{} })), "() => Promise<{ Name: string }>")
+}
+
+func TestTagParser(t *testing.T) {
+	type Profile struct {
+		Name  string `path:"user.name"`
+		Email string `path:"user.contact.email,omitempty"`
+		Token string `path:"-"`
+		Age   int
+	}
+	c := NewConverter()
+	c.TagParser = func(tag reflect.StructTag) (string, []string, bool) {
+		v, ok := tag.Lookup("path")
+		if !ok {
+			return "", nil, false
+		}
+		if v == "-" {
+			return "", nil, true
+		}
+		parts := strings.Split(v, ",")
+		path := strings.Split(parts[0], ".")
+		return path[len(path)-1], parts[1:], false
+	}
+	expect(t, c.Convert(typ(Profile{})), "{ name: string, email?: string, Age: number }")
+}
+
+func TestCatchAll(t *testing.T) {
+	type Labels struct {
+		Name  string            `json:"name"`
+		Count int               `json:"count,omitempty"`
+		Extra map[string]string `ts:",inline"`
+	}
+	type Anything struct {
+		Name  string                 `json:"name"`
+		Extra map[string]interface{} `ts:",inline"`
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(Labels{})), "{ name: string, count?: number, [k: string]: string }")
+	expect(t, c.Convert(typ(struct {
+		Extra map[string]int `ts:",inline"`
+	}{})), "{ [k: string]: number }")
+	c.WidenIndexSignatures = true
+	expect(t, c.Convert(typ(Labels{})), "{ name: string, count?: number, [k: string]: string | number | undefined }")
+	expect(t, c.Convert(typ(Anything{})), "{ name: string, [k: string]: any }")
+	if err := Validate(c.Convert(typ(Labels{}))); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, c.ConvertZod(typ(Labels{})), "z.object({ name: z.string(), count: z.number().optional() }).catchall(z.string())")
+}
+
+type Audit struct {
+	ID      string
+	Created string
+}
+type Meta struct {
+	ID      string
+	Created int
+	Version int
+}
+type TaggedMeta struct {
+	Created int `json:"Created"`
+}
+type AuditA struct{ Audit }
+type AuditB struct{ Audit }
+
+func TestEmbeddedCollisions(t *testing.T) {
+	var errs []error
+	c := NewConverter()
+	c.OnError = func(err error) { errs = append(errs, err) }
+	// ambiguous fields at the same depth are dropped
+	expect(t, c.Convert(typ(struct {
+		Audit
+		Meta
+		Name string
+	}{})), "{ Version: number, Name: string }")
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors but got %d", len(errs))
+	}
+	// least nested field wins
+	expect(t, c.Convert(typ(struct {
+		Audit
+		Meta
+		ID      string `json:"id"`
+		Created bool
+	}{})), "{ Version: number, id: string, Created: boolean }")
+	// tagged field wins at the same depth
+	expect(t, c.Convert(typ(struct {
+		Audit
+		TaggedMeta
+	}{})), "{ ID: string, Created: number }")
+	// the same type embedded twice at the same depth is ambiguous
+	expect(t, c.Convert(typ(struct {
+		AuditA
+		AuditB
+		Name string
+	}{})), "{ Name: string }")
+}
+
+func TestMultiLineSeparators(t *testing.T) {
+	type Point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+	c := NewConverter()
+	c.TrailingComma = true
+	expect(t, c.Convert(typ(Point{})), "{ x: number, y: number }")
+	c.Indent = "  "
+	expect(t, c.Convert(typ(Point{})), "{\n  x: number,\n  y: number,\n}")
+	c.FieldSeparator = ";"
+	expect(t, c.Convert(typ(Point{})), "{\n  x: number;\n  y: number;\n}")
+	c.TrailingComma = false
+	expect(t, c.Convert(typ(Point{})), "{\n  x: number;\n  y: number\n}")
+	if err := Validate(c.Convert(typ(Point{}))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFuncsParamNames(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(func(*[]User) {})), "(user: Array<{ Name: string }>) => Promise<void>")
+	expect(t, c.Convert(typ(func(**User) {})), "(user: { Name: string }) => Promise<void>")
+	expect(t, c.Convert(typ(func([]*User, [2]User) {})), "(user: Array<{ Name: string }>, user1: Array<{ Name: string }>) => Promise<void>")
+	expect(t, c.Convert(typ(func(chan *User) {})), "(user: AsyncIterable<{ Name: string }>) => Promise<void>")
+	expect(t, c.Convert(typ(func(*[]string) {})), "(str: Array<string>) => Promise<void>")
+	expect(t, c.Convert(typ(func(*struct{}) {})), "(_: {}) => Promise<void>")
+	// names are for the params in the declaration
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{ParamNames: []string{"a", "b"}} }
+	expect(t, c.Convert(typ(func(context.Context, string, int) {})), "(a: string, b: number) => Promise<void>")
+	expect(t, c.Convert(typ(func(string, context.Context, int) {})), "(a: string, b: number) => Promise<void>")
+	m, _ := typ(Service{}).MethodByName("Get")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf {
+		return FuncConf{IsMethod: true, MethodName: "get", ParamNames: []string{"id"}}
+	}
+	expect(t, c.Convert(m.Type), "get (id: string): Promise<{ Name: string }>")
+}
+
+func TestJSONTagsDash(t *testing.T) {
+	c := NewConverter()
+	expect(t, c.Convert(typ(struct {
+		Skipped string `json:"-"`
+		Dash    string `json:"-,"`
+	}{})), "{ \"-\": string }")
+	expect(t, c.Convert(typ(struct {
+		Dash int `json:"-,omitempty"`
+	}{})), "{ \"-\"?: number }")
+	expect(t, c.ConvertZod(typ(struct {
+		Dash string `json:"-,"`
+	}{})), "z.object({ \"-\": z.string() })")
+	if err := Validate(c.Convert(typ(struct {
+		Dash string `json:"-,"`
+		Name string `json:"name"`
+	}{}))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJSONTagsOddNames(t *testing.T) {
+	type Odd struct {
+		FirstName string `json:"first name,omitempty"`
+		Ratio     int    `json:"a:b"`
+		Quoted    string `json:"a\"b"`
+		Options   string `json:"opts,omitempty,string"`
+	}
+	c := NewConverter()
+	expect(t, c.Convert(typ(Odd{})), "{ \"first name\"?: string, \"a:b\": number, Quoted: string, opts?: string }")
+	if err := Validate(c.Convert(typ(Odd{}))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type Search struct{}
+
+func (Search) Find(query string, limit int) ([]User, error) { return nil, nil }
+
+func TestFuncsOverloads(t *testing.T) {
+	c := NewConverter()
+	c.ConfigureFunc = func(t reflect.Type) FuncConf {
+		return FuncConf{ParamNames: []string{"query", "limit"}, Overloads: [][]string{{"query"}, {"query", "limit"}}}
+	}
+	fn := typ(func(string, int) []User { return nil })
+	expect(t, c.Convert(fn), "{ (query: string): Promise<Array<{ Name: string }>>; (query: string, limit: number): Promise<Array<{ Name: string }>> }")
+	if err := Validate(c.Convert(fn)); err != nil {
+		t.Fatal(err)
+	}
+	m, _ := typ(Search{}).MethodByName("Find")
+	c.ConfigureFunc = func(t reflect.Type) FuncConf {
+		return FuncConf{IsMethod: true, MethodName: "Find", Overloads: [][]string{{"str"}, {"str", "int"}}}
+	}
+	expect(t, c.Convert(m.Type), "Find (str: string): Promise<Array<{ Name: string }>>\nFind (str: string, int: number): Promise<Array<{ Name: string }>>")
+
+	c.ConfigureFunc = func(t reflect.Type) FuncConf { return FuncConf{Overloads: [][]string{{"nope"}}} }
+	if _, err := c.TryConvert(fn); err == nil || !strings.Contains(err.Error(), "unknown parameter in overload") {
+		t.Fatalf("expected unknown overload param error but got %v", err)
+	}
+}
+
+func TestRecordMaps(t *testing.T) {
+	c := NewConverter()
+	c.RecordMaps = true
+	expect(t, c.Convert(typ(map[string]int{})), "Record<string, number>")
+	c.MapValueOptional = true
+	expect(t, c.Convert(typ(map[string]int{})), "Partial<Record<string, number>>")
+	expect(t, c.Convert(typ(map[string]User{})), "Partial<Record<string, { Name: string }>>")
+	c.BoolKeyLiterals = true
+	expect(t, c.Convert(typ(map[bool]int{})), "Partial<Record<\"true\" | \"false\", number>>")
+	c.RecordMaps = false
+	expect(t, c.Convert(typ(map[string]int{})), "{ [k: string]: number | undefined }")
+}
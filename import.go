@@ -0,0 +1,47 @@
+package go2ts
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ImportedType is a typescript type that is imported from another module.
+type ImportedType struct {
+	// Name is the exported name of the type.
+	Name string
+	// From is the module the type is imported from e.g. "./user".
+	From string
+}
+
+// AddTypesWithImport adds custom types that are imported from other modules.
+// Converter.File outputs import type statements for the types that are used.
+func (c *Converter) AddTypesWithImport(importedTypes map[reflect.Type]ImportedType) {
+	for k, v := range importedTypes {
+		c.types[k] = v.Name
+		c.imports[k] = v
+	}
+}
+
+// importStatements returns import type statements for the imported types that
+// have been used, one per module, ordered by module.
+func (c *Converter) importStatements() []string {
+	names := make(map[string][]string)
+	for t := range c.usedImports {
+		it := c.imports[t]
+		names[it.From] = append(names[it.From], it.Name)
+	}
+	var froms []string
+	for from := range names {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+
+	var stmts []string
+	for _, from := range froms {
+		sort.Strings(names[from])
+		stmts = append(stmts, fmt.Sprintf("import type { %s } from %q", strings.Join(names[from], ", "), from))
+	}
+	return stmts
+}
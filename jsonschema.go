@@ -0,0 +1,123 @@
+package go2ts
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect output by ConvertJSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// ConvertJSONSchema takes a golang reflect.Type and returns a JSON Schema
+// document that describes its JSON encoding. Struct fields are named, omitted
+// and made optional (i.e. not required) in the same way as Converter.Convert
+// and pointers are nullable in PointerModeNull.
+//
+// An error is returned for types that cannot be encoded as JSON e.g.
+// functions and channels, and for recursive types.
+func (c *Converter) ConvertJSONSchema(t reflect.Type) (map[string]interface{}, error) {
+	s, err := c.jsonSchema(t, make(map[reflect.Type]bool))
+	if err != nil {
+		return nil, err
+	}
+	s["$schema"] = JSONSchemaDraft
+	return s, nil
+}
+
+func (c *Converter) jsonSchema(t reflect.Type, visiting map[reflect.Type]bool) (map[string]interface{}, error) {
+	if members, ok := c.enums[t]; ok {
+		var values []interface{}
+		for _, m := range members {
+			values = append(values, m.Value)
+		}
+		return map[string]interface{}{"enum": values}, nil
+	}
+
+	kind := t.Kind()
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case t == jsonNumberType:
+		return map[string]interface{}{"type": []interface{}{"number", "string"}}, nil
+	case isMarshaler(t):
+		return map[string]interface{}{}, nil
+	case kind == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case kind == reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case isInt(kind):
+		return map[string]interface{}{"type": "integer"}, nil
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case kind == reflect.Interface:
+		return map[string]interface{}{}, nil
+	case kind == reflect.Ptr:
+		s, err := c.jsonSchema(t.Elem(), visiting)
+		if err != nil || c.PointerMode != PointerModeNull {
+			return s, err
+		}
+		return map[string]interface{}{
+			"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}},
+		}, nil
+	case kind == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+	case kind == reflect.Slice || kind == reflect.Array:
+		items, err := c.jsonSchema(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		s := map[string]interface{}{"type": "array", "items": items}
+		if kind == reflect.Array {
+			s["minItems"] = t.Len()
+			s["maxItems"] = t.Len()
+		}
+		return s, nil
+	case kind == reflect.Map:
+		values, err := c.jsonSchema(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	case kind == reflect.Struct:
+		if visiting[t] {
+			return nil, fmt.Errorf("unhandled recursive type: %v", t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		s := map[string]interface{}{"type": "object"}
+		props := make(map[string]interface{})
+		var required []string
+		for _, f := range c.structFields(t) {
+			if f.Inline {
+				values, err := c.jsonSchema(f.GoType.Elem(), visiting)
+				if err != nil {
+					return nil, err
+				}
+				s["additionalProperties"] = values
+				continue
+			}
+			ps := map[string]interface{}{"type": "string"}
+			if !f.Quoted {
+				var err error
+				ps, err = c.jsonSchema(f.GoType, visiting)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if f.Deprecated != nil {
+				ps["deprecated"] = true
+			}
+			props[f.Name] = ps
+			if !f.Optional {
+				required = append(required, f.Name)
+			}
+		}
+		s["properties"] = props
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unhandled type: %v (%s)", t, t.Kind())
+}
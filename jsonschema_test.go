@@ -0,0 +1,65 @@
+package go2ts
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func expectSchema(t *testing.T, c *Converter, i interface{}, expected string) {
+	t.Helper()
+	s, err := c.ConvertJSONSchema(typ(i))
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(s, "$schema")
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, string(b), expected)
+}
+
+func TestConvertJSONSchema(t *testing.T) {
+	type Profile struct {
+		Name    string    `json:"name"`
+		Email   string    `json:"email,omitempty"`
+		Age     int       `json:"age"`
+		Score   float64   `json:"score,omitempty"`
+		Tags    []string  `json:"tags"`
+		Created time.Time `json:"created"`
+		Owner   *User     `json:"owner,omitempty"`
+	}
+	c := NewConverter()
+	s, err := c.ConvertJSONSchema(typ(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, s["$schema"].(string), JSONSchemaDraft)
+
+	expectSchema(t, c, "", `{"type":"string"}`)
+	expectSchema(t, c, [2]int{}, `{"items":{"type":"integer"},"maxItems":2,"minItems":2,"type":"array"}`)
+	expectSchema(t, c, map[string]bool{}, `{"additionalProperties":{"type":"boolean"},"type":"object"}`)
+	expectSchema(t, c, Profile{}, `{"properties":{"age":{"type":"integer"},"created":{"format":"date-time","type":"string"},"email":{"type":"string"},"name":{"type":"string"},"owner":{"properties":{"Name":{"type":"string"}},"required":["Name"],"type":"object"},"score":{"type":"number"},"tags":{"items":{"type":"string"},"type":"array"}},"required":["name","age","tags","created"],"type":"object"}`)
+
+	expectSchema(t, c, struct {
+		Name  string            `json:"name"`
+		Extra map[string]string `ts:",inline"`
+	}{}, `{"additionalProperties":{"type":"string"},"properties":{"name":{"type":"string"}},"required":["name"],"type":"object"}`)
+
+	c.PointerMode = PointerModeNull
+	expectSchema(t, c, struct{ Owner *User }{}, `{"properties":{"Owner":{"anyOf":[{"properties":{"Name":{"type":"string"}},"required":["Name"],"type":"object"},{"type":"null"}]}},"required":["Owner"],"type":"object"}`)
+
+	c.AddEnum(typ(Color("")), []Const{{"Red", "red"}, {"Green", "green"}})
+	expectSchema(t, c, struct{ Color Color }{}, `{"properties":{"Color":{"enum":["red","green"]}},"required":["Color"],"type":"object"}`)
+}
+
+func TestConvertJSONSchemaErrors(t *testing.T) {
+	c := NewConverter()
+	if _, err := c.ConvertJSONSchema(typ(func() {})); err == nil {
+		t.Fatal("expected error converting func")
+	}
+	if _, err := c.ConvertJSONSchema(typ(Node{})); err == nil {
+		t.Fatal("expected error converting recursive type")
+	}
+}
@@ -0,0 +1,450 @@
+package go2ts
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ParseEnums parses the golang package in the passed directory and returns
+// the exported constants for each named type declared in the package, keyed
+// by type name and in source order. For example:
+//
+//	type State int
+//
+//	const (
+//		Pending State = iota
+//		Active
+//	)
+//
+// Results in {"State": [{Pending 0} {Active 1}]}.
+func ParseEnums(dir string) (map[string][]Const, error) {
+	_, tpkg, err := checkPackage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var consts []*types.Const
+	scope := tpkg.Scope()
+	for _, name := range scope.Names() {
+		k, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !k.Exported() {
+			continue
+		}
+		named, ok := k.Type().(*types.Named)
+		if !ok || named.Obj().Pkg() != tpkg {
+			continue
+		}
+		consts = append(consts, k)
+	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+
+	enums := make(map[string][]Const)
+	for _, k := range consts {
+		name := k.Type().(*types.Named).Obj().Name()
+		enums[name] = append(enums[name], Const{Name: k.Name(), Value: constValue(k.Val())})
+	}
+	return enums, nil
+}
+
+// checkPackage parses and type checks the single (non test) golang package in
+// a directory.
+func checkPackage(dir string) (*ast.Package, *types.Package, error) {
+	fset := token.NewFileSet()
+	pkg, err := parsePackage(fset, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []*ast.File
+	for _, f := range pkg.Files {
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	tpkg, err := conf.Check(pkg.Name, fset, files, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("type checking %s: %w", dir, err)
+	}
+	return pkg, tpkg, nil
+}
+
+// parsePackage parses the single (non test) golang package in a directory.
+func parsePackage(fset *token.FileSet, dir string) (*ast.Package, error) {
+	notTest := func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(fset, dir, notTest, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected 1 package in %s but found %d", dir, len(pkgs))
+	}
+	for _, pkg := range pkgs {
+		return pkg, nil
+	}
+	return nil, nil
+}
+
+// constValue converts a constant value to its golang equivalent.
+func constValue(v constant.Value) interface{} {
+	switch v.Kind() {
+	case constant.Bool:
+		return constant.BoolVal(v)
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Int:
+		if i, ok := constant.Int64Val(v); ok {
+			return i
+		}
+		u, _ := constant.Uint64Val(v)
+		return u
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return f
+	}
+	return v.ExactString()
+}
+
+// ConvertPackage parses the golang package in the passed directory and returns
+// typescript declarations for its exported types and functions, in source
+// order. Unlike Converter.Convert, generic types are supported and are
+// declared with type parameters e.g. type Box[T any] struct{ Value T } is
+// declared as export interface Box<T> { Value: T }, and instantiations are
+// referenced as Box<string>.
+//
+// Exported types of the package are referenced by name. Struct fields are
+// named, omitted and quoted according to their json tags (or
+// Converter.TagParser), and the ts tag ",inline" and ",readonly" options
+// apply. However, ambiguous promoted fields of embedded structs are not
+// dropped as they are by encoding/json. Converter.PointerMode,
+// Converter.TimeType and Converter.StructStyle apply, but other converter
+// options do not. Interface types are not declared, and are converted to any.
+//
+// Functions are declared with a JSDoc comment from their doc comment, with
+// @param and @returns tags that describe the golang types of the parameters
+// and return values e.g.
+// export declare function Greet (user: User): Promise<string>
+func (c *Converter) ConvertPackage(dir string) (ts string, err error) {
+	pkg, tpkg, err := checkPackage(dir)
+	if err != nil {
+		return "", err
+	}
+
+	defer recoverConvertError(&err)
+
+	var objs []types.Object
+	scope := tpkg.Scope()
+	for _, name := range scope.Names() {
+		switch obj := scope.Lookup(name).(type) {
+		case *types.TypeName:
+			if !obj.Exported() || obj.IsAlias() {
+				continue
+			}
+			if _, ok := obj.Type().Underlying().(*types.Interface); ok {
+				continue
+			}
+			objs = append(objs, obj)
+		case *types.Func:
+			if obj.Exported() {
+				objs = append(objs, obj)
+			}
+		}
+	}
+	sort.Slice(objs, func(i, j int) bool { return objs[i].Pos() < objs[j].Pos() })
+
+	docs := funcDocs(pkg)
+	var decls []string
+	for _, obj := range objs {
+		if fn, ok := obj.(*types.Func); ok {
+			decls = append(decls, c.sourceFuncDeclaration(fn, docs[fn.Name()]))
+			continue
+		}
+		decls = append(decls, c.sourceDeclaration(obj.Type().(*types.Named)))
+	}
+	if len(decls) == 0 {
+		return "", nil
+	}
+	return strings.Join(decls, "\n\n") + "\n", nil
+}
+
+// funcDocs returns the doc comments of the package level functions of a
+// package, keyed by function name.
+func funcDocs(pkg *ast.Package) map[string]string {
+	docs := make(map[string]string)
+	for _, f := range pkg.Files {
+		for _, d := range f.Decls {
+			if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Doc != nil {
+				docs[fd.Name.Name] = strings.TrimSpace(fd.Doc.Text())
+			}
+		}
+	}
+	return docs
+}
+
+// sourceFuncDeclaration converts a function parsed from source to a
+// typescript function declaration, with a JSDoc comment.
+func (c *Converter) sourceFuncDeclaration(fn *types.Func, doc string) string {
+	sig := fn.Type().(*types.Signature)
+	finfo := c.sourceFunc(sig, fn.Pkg())
+	var lines []string
+	if doc != "" {
+		lines = append(lines, strings.Split(doc, "\n")...)
+	}
+	// params are described by their golang type, which may be more specific
+	// than the typescript type e.g. int64
+	var gotypes []string
+	for i := 0; i < sig.Params().Len(); i++ {
+		if t := sig.Params().At(i).Type(); !isSourceContext(t) {
+			gotypes = append(gotypes, types.TypeString(t, types.RelativeTo(fn.Pkg())))
+		}
+	}
+	for i, p := range finfo.Params {
+		lines = append(lines, fmt.Sprintf("@param %s - golang %s", p.Name, gotypes[i]))
+	}
+	// a trailing error is thrown rather than returned
+	var rets []string
+	for i := 0; i < sig.Results().Len(); i++ {
+		t := sig.Results().At(i).Type()
+		if i == sig.Results().Len()-1 && isSourceError(t) {
+			break
+		}
+		rets = append(rets, types.TypeString(t, types.RelativeTo(fn.Pkg())))
+	}
+	if len(rets) > 0 {
+		lines = append(lines, "@returns golang "+strings.Join(rets, ", "))
+	}
+	decl := fmt.Sprintf("export declare function %s (%s): %s", fn.Name(), formatParams(finfo.Params), finfo.Returns)
+	if len(lines) == 0 {
+		return decl
+	}
+	return jsdoc(lines, "") + "\n" + decl
+}
+
+// sourceFunc extracts type information about a function signature parsed
+// from source. Functions are assumed to be async, context params are ignored
+// and a trailing error return is removed. Unlike Converter.Convert, comma-ok
+// returns are returned as an array and Converter.ConfigureFunc is not used.
+func (c *Converter) sourceFunc(sig *types.Signature, pkg *types.Package) *funcInfo {
+	finfo := funcInfo{Returns: "void"}
+	var rets []string
+	for i := 0; i < sig.Results().Len(); i++ {
+		t := sig.Results().At(i).Type()
+		if i == sig.Results().Len()-1 && isSourceError(t) {
+			break
+		}
+		rets = append(rets, c.convertSource(t, pkg))
+	}
+	if len(rets) == 1 {
+		finfo.Returns = rets[0]
+	} else if len(rets) > 1 {
+		finfo.Returns = fmt.Sprintf("[%s]", strings.Join(rets, ", "))
+	}
+	finfo.Returns = fmt.Sprintf("Promise<%s>", finfo.Returns)
+
+	for i := 0; i < sig.Params().Len(); i++ {
+		v := sig.Params().At(i)
+		if isSourceContext(v.Type()) {
+			continue
+		}
+		name := v.Name()
+		if name == "" {
+			name = "_"
+		}
+		rest := sig.Variadic() && i == sig.Params().Len()-1
+		finfo.appendParam(param{name, c.convertSource(v.Type(), pkg), rest})
+	}
+	return &finfo
+}
+
+// isSourceQuotable determines if the json ",string" tag option applies to a
+// type parsed from source (see isQuotable).
+func isSourceQuotable(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0 && b.Info()&types.IsComplex == 0
+}
+
+// isSourceError determines if a type parsed from source implements error.
+func isSourceError(t types.Type) bool {
+	return types.Implements(t, types.Universe.Lookup("error").Type().Underlying().(*types.Interface))
+}
+
+// isSourceContext determines if a type parsed from source is context.Context.
+func isSourceContext(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "context" && n.Obj().Name() == "Context"
+}
+
+// sourceDeclaration converts a named type parsed from source to a typescript
+// declaration.
+func (c *Converter) sourceDeclaration(t *types.Named) string {
+	name := t.Obj().Name()
+	if tps := t.TypeParams(); tps.Len() > 0 {
+		var params []string
+		for i := 0; i < tps.Len(); i++ {
+			params = append(params, tps.At(i).Obj().Name())
+		}
+		name = fmt.Sprintf("%s<%s>", name, strings.Join(params, ", "))
+	}
+	pkg := t.Obj().Pkg()
+	if st, ok := t.Underlying().(*types.Struct); ok && c.StructStyle == StructStyleInterface {
+		return fmt.Sprintf("export interface %s %s", name, c.convertSourceStruct(st, pkg))
+	}
+	return fmt.Sprintf("export type %s = %s", name, c.convertSource(t.Underlying(), pkg))
+}
+
+// convertSource converts a type parsed from source to a typescript type,
+// referencing the exported named types of pkg by name.
+func (c *Converter) convertSource(t types.Type, pkg *types.Package) string {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return t.Obj().Name()
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == timeType.PkgPath() && obj.Name() == timeType.Name() {
+			if c.TimeType != "" {
+				return c.TimeType
+			}
+			return "string" // encoded as RFC 3339
+		}
+		if obj.Pkg() != nil && obj.Pkg().Path() == jsonNumberType.PkgPath() && obj.Name() == jsonNumberType.Name() {
+			return "number | string"
+		}
+		// interfaces are not declared (see ConvertPackage)
+		_, isIface := t.Underlying().(*types.Interface)
+		if obj.Pkg() != pkg || !obj.Exported() || isIface {
+			// types that are not referenced by name are converted inline, so
+			// recursive types cannot be converted
+			key := t.String()
+			if c.convertingSource[key] {
+				panic(convertErrorf("unhandled recursive type: %v", t))
+			}
+			c.convertingSource[key] = true
+			defer delete(c.convertingSource, key)
+			return c.convertSource(t.Underlying(), pkg)
+		}
+		targs := t.TypeArgs()
+		if targs.Len() == 0 {
+			return obj.Name()
+		}
+		var args []string
+		for i := 0; i < targs.Len(); i++ {
+			args = append(args, c.convertSource(targs.At(i), pkg))
+		}
+		return fmt.Sprintf("%s<%s>", obj.Name(), strings.Join(args, ", "))
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return "boolean"
+		case t.Info()&types.IsNumeric != 0:
+			return "number"
+		case t.Info()&types.IsString != 0:
+			return "string"
+		}
+	case *types.Pointer:
+		ts := c.convertSource(t.Elem(), pkg)
+		if c.PointerMode == PointerModeNull && (!strings.HasSuffix(ts, " | null") || isFuncType(ts)) {
+			ts = union(ts, "null")
+		}
+		return ts
+	case *types.Slice:
+		if b, ok := t.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Uint8 {
+			return "string" // []byte is encoded as a base64 string
+		}
+		return fmt.Sprintf("Array<%s>", c.convertSource(t.Elem(), pkg))
+	case *types.Array:
+		return fmt.Sprintf("Array<%s>", c.convertSource(t.Elem(), pkg))
+	case *types.Map:
+		return fmt.Sprintf("{ [k: string]: %s }", c.convertSource(t.Elem(), pkg))
+	case *types.Chan:
+		return fmt.Sprintf("AsyncIterable<%s>", c.convertSource(t.Elem(), pkg))
+	case *types.Interface:
+		return "any"
+	case *types.Struct:
+		return c.convertSourceStruct(t, pkg)
+	case *types.Signature:
+		finfo := c.sourceFunc(t, pkg)
+		return fmt.Sprintf("(%s) => %s", formatParams(finfo.Params), finfo.Returns)
+	}
+	// aliases e.g. any are converted as the type they refer to
+	if u := t.Underlying(); u != t {
+		return c.convertSource(u, pkg)
+	}
+	panic(convertErrorf("unhandled type: %v", t))
+}
+
+// convertSourceStruct converts a struct parsed from source to a typescript
+// object type.
+func (c *Converter) convertSourceStruct(t *types.Struct, pkg *types.Package) string {
+	props := c.sourceProps(nil, t, pkg)
+	if len(props) == 0 {
+		return "{}"
+	}
+	return fmt.Sprintf("{ %s }", strings.Join(props, ", "))
+}
+
+// sourceProps appends the typescript properties for the fields of a struct
+// parsed from source. Fields of embedded structs are promoted to the parent.
+func (c *Converter) sourceProps(props []string, t *types.Struct, pkg *types.Package) []string {
+	for i := 0; i < t.NumFields(); i++ {
+		f := t.Field(i)
+		name, opts, skip := c.parseTag(reflect.StructTag(t.Tag(i)))
+		if skip {
+			continue
+		}
+		if f.Embedded() && name == "" {
+			ft := f.Type()
+			if p, ok := ft.(*types.Pointer); ok {
+				ft = p.Elem()
+			}
+			if st, ok := ft.Underlying().(*types.Struct); ok && ft.String() != timeType.String() {
+				// fields of a recursively embedded struct are already promoted
+				key := ft.String()
+				if !c.convertingSource[key] {
+					c.convertingSource[key] = true
+					props = c.sourceProps(props, st, pkg)
+					delete(c.convertingSource, key)
+				}
+				continue
+			}
+		}
+		if !f.Exported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name()
+		}
+		_, tsOpts := parseTSTag(reflect.StructTag(t.Tag(i)))
+		prefix := ""
+		if hasOption(tsOpts, "readonly") {
+			prefix = "readonly "
+		}
+		if m, ok := f.Type().Underlying().(*types.Map); ok && hasOption(tsOpts, "inline") {
+			props = append(props, fmt.Sprintf("%s[k: string]: %s", prefix, c.convertSource(m.Elem(), pkg)))
+			continue
+		}
+		name = propName(name)
+		_, isPtr := f.Type().(*types.Pointer)
+		if hasOption(opts, "omitempty") || (isPtr && c.PointerMode == PointerModeOptional) {
+			name += "?"
+		}
+		ts := c.convertSource(f.Type(), pkg)
+		if hasOption(opts, "string") && isSourceQuotable(f.Type()) {
+			ts = "string" // value is encoded within a JSON string
+			if isPtr && c.PointerMode == PointerModeNull {
+				ts = union(ts, "null")
+			}
+		}
+		props = append(props, fmt.Sprintf("%s%s: %s", prefix, name, ts))
+	}
+	return props
+}
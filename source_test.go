@@ -0,0 +1,180 @@
+package go2ts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type State int
+type Color string
+
+func TestParseEnums(t *testing.T) {
+	enums, err := ParseEnums("testdata/enum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enums) != 2 {
+		t.Fatalf("expected 2 enums but got %d", len(enums))
+	}
+	expected := []Const{{"Pending", int64(0)}, {"Active", int64(1)}, {"Closed", int64(2)}}
+	if !reflect.DeepEqual(enums["State"], expected) {
+		t.Fatalf("expected %v to equal %v", enums["State"], expected)
+	}
+	expected = []Const{{"Red", "red"}, {"Green", "green"}}
+	if !reflect.DeepEqual(enums["Color"], expected) {
+		t.Fatalf("expected %v to equal %v", enums["Color"], expected)
+	}
+
+	c := NewConverter()
+	c.AddEnum(typ(State(0)), enums["State"])
+	c.AddEnum(typ(Color("")), enums["Color"])
+	expect(t, c.Convert(typ(struct{ State State }{})), "{ State: State }")
+	expect(t, c.File(), "export enum State { Pending = 0, Active = 1, Closed = 2 }\n\nexport enum Color { Red = \"red\", Green = \"green\" }\n")
+}
+
+func TestParseEnumsError(t *testing.T) {
+	_, err := ParseEnums("testdata/missing")
+	if err == nil {
+		t.Fatal("expected error parsing missing directory")
+	}
+}
+
+func TestEnumStyles(t *testing.T) {
+	c := NewConverter()
+	c.AddEnum(typ(Color("")), []Const{{"Red", "red"}, {"Green", "green"}})
+	c.EnumStyle = EnumStyleUnion
+	expect(t, c.File(), "export type Color = \"red\" | \"green\"\n")
+	c.EnumStyle = EnumStyleUnionConst
+	expect(t, c.File(), "export type Color = \"red\" | \"green\"\nexport const Color = { Red: \"red\", Green: \"green\" } as const\n")
+	expect(t, c.Convert(typ(struct{ Color Color }{})), "{ Color: Color }")
+}
+
+func TestEnumNameTypes(t *testing.T) {
+	c := NewConverter()
+	c.AddEnum(typ(State(0)), []Const{{"Pending", 0}, {"Active", 1}})
+	c.EnumNameTypes = true
+	expect(t, c.File(), "export enum State { Pending = 0, Active = 1 }\nexport type StateName = keyof typeof State\n")
+	c.EnumStyle = EnumStyleUnion
+	expect(t, c.File(), "export type State = 0 | 1\n")
+}
+
+func TestConstGroups(t *testing.T) {
+	c := NewConverter()
+	c.AddConstGroup("Limits", []Const{{"MaxUsers", 10}, {"MaxTeams", 3}})
+	expect(t, c.File(), "export const Limits = { MaxUsers: 10, MaxTeams: 3 } as const\n")
+	c.SatisfiesConstGroups = true
+	expect(t, c.File(), "export const Limits = { MaxUsers: 10, MaxTeams: 3 } as const satisfies Record<string, number>\n")
+}
+
+func TestConvertPackage(t *testing.T) {
+	c := NewConverter()
+	ts, err := c.ConvertPackage("testdata/generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, ts, "export interface Box<T> { value: T }\n\nexport interface Pair<K, V> { key: K, value: V }\n\nexport interface Order { id: string, items: Array<Box<string>>, meta?: Pair<string, number> }\n\nexport interface List<T> { items: Array<T>, total: number, next?: string }\n\nexport interface Users { items: Array<Order>, total: number, next?: string }\n\nexport interface Owner { pet: any, pets: Array<any> }\n\nexport interface Stats { count: string, ratio: string, readonly id: string, [k: string]: string, labels: Array<string> }\n")
+}
+
+func TestConvertPackageRecursive(t *testing.T) {
+	c := NewConverter()
+	ts, err := c.ConvertPackage("testdata/recursive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, ts, "export interface Tree<T> { value: T, children: Array<Tree<T>> }\n\nexport interface Forest { trees: Array<Tree<string>> }\n\nexport interface Chain { id: string, elem: { Value: any } }\n")
+
+	_, err = c.ConvertPackage("testdata/cycle")
+	if err == nil || !strings.Contains(err.Error(), "recursive") {
+		t.Fatalf("expected recursive type error but got %v", err)
+	}
+}
+
+func TestEnumKeyedMaps(t *testing.T) {
+	c := NewConverter()
+	c.AddEnum(typ(State(0)), []Const{{"Pending", 1}, {"Active", 2}, {"Closed", 3}})
+	c.AddEnum(typ(Color("")), []Const{{"Red", "red"}, {"Green", "green"}})
+	expect(t, c.Convert(typ(map[State]User{})), "Partial<Record<1 | 2 | 3, { Name: string }>>")
+	expect(t, c.Convert(typ(map[Color]int{})), "Partial<Record<\"red\" | \"green\", number>>")
+	c.IntKeyMapsAsArrays = true
+	c.MapValueOptional = true
+	expect(t, c.Convert(typ(map[State]User{})), "Partial<Record<1 | 2 | 3, { Name: string }>>")
+	c.MapValueOptional = false
+	c.ExhaustiveEnumKeys = true
+	expect(t, c.Convert(typ(map[State]User{})), "Record<1 | 2 | 3, { Name: string }>")
+	expect(t, c.Convert(typ(map[Color]int{})), "Record<\"red\" | \"green\", number>")
+	c.MapValueOptional = true
+	expect(t, c.Convert(typ(map[Color]int{})), "Partial<Record<\"red\" | \"green\", number>>")
+}
+
+func TestSingleMemberEnums(t *testing.T) {
+	type Kind string
+	type Event struct {
+		Type Kind   `json:"type"`
+		Name string `json:"name"`
+	}
+	c := NewConverter()
+	c.AddEnum(typ(Kind("")), []Const{{"KindUser", "user"}})
+	expect(t, c.Convert(typ(Event{})), "{ type: \"user\", name: string }")
+	c.AddEnum(typ(State(0)), []Const{{"Pending", 0}, {"Active", 1}})
+	expect(t, c.Convert(typ(State(0))), "State")
+}
+
+func TestConvertPackageStructStyle(t *testing.T) {
+	c := NewConverter()
+	c.StructStyle = StructStyleTypeAlias
+	ts, err := c.ConvertPackage("testdata/generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(ts, "export type Box<T> = { value: T }\n") {
+		t.Fatalf("expected type alias declaration but got %q", ts)
+	}
+}
+
+func TestConvertPackageFuncs(t *testing.T) {
+	c := NewConverter()
+	ts, err := c.ConvertPackage("testdata/funcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, ts, `export interface User { name: string }
+
+/**
+ * Greet returns a greeting for a user.
+ *
+ * The greeting is shouted if excited is set.
+ * @param user - golang User
+ * @param excited - golang bool
+ * @returns golang string
+ */
+export declare function Greet (user: User, excited: boolean): Promise<string>
+
+/** Close closes all connections. */
+export declare function Close (): Promise<void>
+
+/**
+ * @param user - golang *User
+ * @param tags - golang []string
+ */
+export declare function Tag (user: User, ...tags: Array<string>): Promise<void>
+`)
+}
+
+func TestPointerEnums(t *testing.T) {
+	type Task struct {
+		State  *State `json:"state,omitempty"`
+		Color  *Color `json:"color"`
+		States []*State
+	}
+	c := NewConverter()
+	c.AddEnum(typ(State(0)), []Const{{"Pending", 0}, {"Active", 1}})
+	c.AddEnum(typ(Color("")), []Const{{"Red", "red"}})
+	expect(t, c.Convert(typ(Task{})), "{ state?: State, color: \"red\", States: Array<State> }")
+	c.PointerMode = PointerModeNull
+	expect(t, c.Convert(typ(Task{})), "{ state?: State | null, color: \"red\" | null, States: Array<State | null> }")
+	c.PointerMode = PointerModeOptional
+	expect(t, c.Convert(typ(Task{})), "{ state?: State, color?: \"red\", States: Array<State> }")
+	c.EnumStyle = EnumStyleUnion
+	expect(t, c.File(), "export type State = 0 | 1\n\nexport type Color = \"red\"\n")
+}
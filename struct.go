@@ -1,5 +1,12 @@
 package go2ts
 
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
 // structInfo is exported information about a golang func.
 type structInfo struct {
 	Name   string
@@ -9,6 +16,141 @@ type structInfo struct {
 
 // field is a struct field.
 type field struct {
-	Name string
-	Type string
+	Name     string
+	Type     string
+	GoType   reflect.Type
+	Optional bool
+	// Quoted flags that the value is encoded within a JSON string, as
+	// specified by the json ",string" tag option.
+	Quoted bool
+	// Inline flags a catch-all map field, whose entries are properties of the
+	// parent struct, as specified by the ts ",inline" tag option. The Type of
+	// an inline field is the type of the map values.
+	Inline bool
+	// TSType overrides the typescript type of time.Time values of the field
+	// (see Converter.TimeType), as specified by the ts tag e.g. `ts:"Date"`.
+	// It is ignored for fields of other types.
+	TSType string
+	// Readonly flags a readonly property, as specified by Converter.Readonly
+	// or the ts ",readonly" tag option.
+	Readonly bool
+	// Depth is the embedding depth of a promoted field, 0 if not promoted.
+	Depth int
+	// Tagged flags that the field is named by its tag.
+	Tagged bool
+	// Minimum and Maximum are the bounds of a numeric field, from the min and
+	// max options of its validator tag.
+	Minimum string
+	Maximum string
+	// Description is the field description, from the tag named by
+	// Converter.DescriptionTag.
+	Description string
+	// Deprecated is the deprecation message, if the field is deprecated.
+	Deprecated *string
+}
+
+// propName returns a typescript property name for a field name, quoting names
+// that are not valid identifiers e.g. "-" or "foo-bar".
+func propName(name string) string {
+	if isIdent(name) {
+		return name
+	}
+	b, _ := json.Marshal(name)
+	return string(b)
+}
+
+// parseJSONTag parses the json struct tag of a field, returning the name and
+// options it specifies. The name is empty if the tag does not override the
+// field name e.g. `json:",omitempty"`. Skip is true if the field should be
+// omitted i.e. `json:"-"`. Note that `json:"-,"` names a field "-".
+func parseJSONTag(tag reflect.StructTag) (name string, opts []string, skip bool) {
+	v, ok := tag.Lookup("json")
+	if !ok {
+		return "", nil, false
+	}
+	if v == "-" {
+		return "", nil, true
+	}
+	// the name is everything before the first comma, there is no escaping
+	parts := strings.Split(v, ",")
+	name = parts[0]
+	if !isValidTagName(name) {
+		name = "" // encoding/json ignores invalid names
+	}
+	return name, parts[1:], false
+}
+
+// isValidTagName determines if a json tag name is valid, according to the
+// same rules as encoding/json. Valid names may still need to be quoted as
+// typescript property names e.g. "first name".
+func isValidTagName(s string) bool {
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", r):
+			// allowed punctuation, but backslash and quotes are reserved
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			return false
+		}
+	}
+	return true
+}
+
+// parseTSTag parses the ts struct tag of a field, returning the typescript
+// type and options it specifies e.g. `ts:",inline"`.
+func parseTSTag(tag reflect.StructTag) (ts string, opts []string) {
+	v, ok := tag.Lookup("ts")
+	if !ok {
+		return "", nil
+	}
+	parts := strings.Split(v, ",")
+	return parts[0], parts[1:]
+}
+
+// validatorOptions returns the options of the validator tag of a field e.g.
+// `validate:"required,min=0"`. The tag key is Converter.ValidatorTag.
+func (c *Converter) validatorOptions(tag reflect.StructTag) []string {
+	key := c.ValidatorTag
+	if key == "" {
+		key = "validate"
+	}
+	v, ok := tag.Lookup(key)
+	if !ok {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// validatorParam returns the parameter of a validator option e.g. "0" for
+// "min=0".
+func validatorParam(opts []string, name string) (string, bool) {
+	for _, o := range opts {
+		if strings.HasPrefix(o, name+"=") {
+			return o[len(name)+1:], true
+		}
+	}
+	return "", false
+}
+
+// hasOption determines if the passed tag options include the option opt.
+func hasOption(opts []string, opt string) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// isQuotable determines if the json ",string" tag option applies to the
+// passed field type i.e. it is a string, float, integer or boolean, or a
+// pointer to one.
+func isQuotable(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return isInt(t.Kind())
 }
@@ -0,0 +1,10 @@
+package cycle
+
+type node struct {
+	Value int   `json:"value"`
+	Next  *node `json:"next"`
+}
+
+type List struct {
+	Head *node `json:"head"`
+}
@@ -0,0 +1,23 @@
+package enum
+
+// State is the state of a thing.
+type State int
+
+const (
+	Pending State = iota
+	Active
+	Closed
+)
+
+// Color is a color.
+type Color string
+
+const (
+	Red   Color = "red"
+	Green Color = "green"
+	// unexported constants are not enum members
+	blue Color = "blue"
+)
+
+// Max is not an enum member since it is not a named type.
+const Max = 3
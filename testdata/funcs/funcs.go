@@ -0,0 +1,23 @@
+package funcs
+
+import "context"
+
+type User struct {
+	Name string `json:"name"`
+}
+
+// Greet returns a greeting for a user.
+//
+// The greeting is shouted if excited is set.
+func Greet(ctx context.Context, user User, excited bool) (string, error) {
+	return "hello " + user.Name, nil
+}
+
+// Close closes all connections.
+func Close() error {
+	return nil
+}
+
+func Tag(user *User, tags ...string) {}
+
+func unexported() {}
@@ -0,0 +1,47 @@
+package generic
+
+type Box[T any] struct {
+	Value T `json:"value"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type Order struct {
+	ID    string             `json:"id"`
+	Items []Box[string]      `json:"items"`
+	Meta  *Pair[string, int] `json:"meta,omitempty"`
+}
+
+type base[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+type List[T any] struct {
+	base[T]
+	Next string `json:"next,omitempty"`
+}
+
+type Users struct {
+	*List[Order]
+}
+
+type Animal interface {
+	Sound() string
+}
+
+type Owner struct {
+	Pet  Animal   `json:"pet"`
+	Pets []Animal `json:"pets"`
+}
+
+type Stats struct {
+	Count  int               `json:"count,string"`
+	Ratio  *float64          `json:"ratio,string"`
+	ID     string            `json:"id" ts:",readonly"`
+	Extra  map[string]string `ts:",inline"`
+	Labels []string          `json:"labels,string"`
+}
@@ -0,0 +1,22 @@
+package recursive
+
+import "container/list"
+
+type Tree[T any] struct {
+	Value    T          `json:"value"`
+	Children []*Tree[T] `json:"children"`
+}
+
+type Forest struct {
+	Trees []Tree[string] `json:"trees"`
+}
+
+type link struct {
+	*link
+	ID string `json:"id"`
+}
+
+type Chain struct {
+	link
+	Elem *list.Element `json:"elem"`
+}
@@ -0,0 +1,62 @@
+package go2ts
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AddUnion adds a named type, typically an interface, as a union of the passed
+// member types. The union and its members are declared (see
+// Converter.Declare) e.g. export type Animal = Dog | Cat. Pointer members are
+// declared as their element type.
+func (c *Converter) AddUnion(t reflect.Type, members ...reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// members are stored as their element types, the union and its guards
+	// reference them by their declared names
+	elems := make([]reflect.Type, len(members))
+	for i, m := range members {
+		for m.Kind() == reflect.Ptr {
+			m = m.Elem()
+		}
+		elems[i] = m
+	}
+	c.unions[t] = elems
+	c.declare(t)
+	for _, m := range elems {
+		c.declare(m)
+	}
+}
+
+// unionDeclaration converts a union to a typescript declaration, followed by
+// type guards for its members if Converter.EmitTypeGuards is set.
+func (c *Converter) unionDeclaration(t reflect.Type) string {
+	name := c.types[t]
+	decl := fmt.Sprintf("export type %s = %s", name, c.unionType(t))
+	if !c.EmitTypeGuards {
+		return decl
+	}
+	disc := c.UnionDiscriminant
+	if disc == "" {
+		disc = "type"
+	}
+	prop := "." + disc
+	if !isIdent(disc) {
+		prop = fmt.Sprintf("[%q]", disc)
+	}
+	for _, mt := range c.unions[t] {
+		m := c.types[mt]
+		decl += fmt.Sprintf("\nexport function is%s(x: %s): x is %s { return x%s === %q }", m, name, m, prop, m)
+	}
+	return decl
+}
+
+// unionType returns the union of the members of a union.
+func (c *Converter) unionType(t reflect.Type) string {
+	var members []string
+	for _, m := range c.unions[t] {
+		members = append(members, c.convert(m))
+	}
+	return union(members...)
+}
@@ -0,0 +1,464 @@
+package go2ts
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Validate performs lightweight structural validation of a typescript type
+// string, as output by the converter. It checks for balanced braces, brackets
+// and parens, valid (or quoted) property names, duplicate properties, reserved
+// words used as parameter names and valid union/intersection syntax. A class
+// method declaration such as "Method (str: string): Promise<void>" is also
+// accepted.
+//
+// It is intended to be used in tests to catch invalid output and is NOT a full
+// typescript parser.
+func Validate(ts string) error {
+	toks, err := tokenize(ts)
+	if err != nil {
+		return err
+	}
+	p := tsParser{toks: toks}
+	if p.peek().kind == tokIdent && p.peekAt(1).text == "(" {
+		p.next()
+		err = p.parseSignature()
+	} else {
+		err = p.parseType()
+	}
+	if err != nil {
+		return err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return t.unexpected()
+	}
+	return nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokPunct
+)
+
+// tsToken is a lexical token in a typescript type string.
+type tsToken struct {
+	kind tokenKind
+	text string
+	pos  int
+	// nl flags that a newline preceded the token.
+	nl bool
+}
+
+func (t tsToken) unexpected() error {
+	if t.kind == tokEOF {
+		return fmt.Errorf("unexpected end of input")
+	}
+	return fmt.Errorf("unexpected %q at offset %d", t.text, t.pos)
+}
+
+// tokenize splits a typescript type string into tokens, discarding whitespace
+// and comments.
+func tokenize(s string) ([]tsToken, error) {
+	var toks []tsToken
+	rs := []rune(s)
+	nl := false
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case r == '\n':
+			nl = true
+			i++
+			continue
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			end := strings.Index(string(rs[i+2:]), "*/")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated comment at offset %d", i)
+			}
+			i += len([]rune(string(rs[i+2:])[:end])) + 4
+			continue
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '/':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+			continue
+		}
+
+		t := tsToken{pos: i, nl: nl}
+		nl = false
+		switch {
+		case r == '"' || r == '\'':
+			j := i + 1
+			for ; j < len(rs) && rs[j] != r; j++ {
+				if rs[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			t.kind, t.text = tokString, string(rs[i:j+1])
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i + 1
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			t.kind, t.text = tokNumber, string(rs[i:j])
+			i = j
+		case isIdentStart(r):
+			j := i + 1
+			for j < len(rs) && isIdentPart(rs[j]) {
+				j++
+			}
+			t.kind, t.text = tokIdent, string(rs[i:j])
+			i = j
+		case strings.HasPrefix(string(rs[i:]), "=>"):
+			t.kind, t.text = tokPunct, "=>"
+			i += 2
+		case strings.HasPrefix(string(rs[i:]), "..."):
+			t.kind, t.text = tokPunct, "..."
+			i += 3
+		case strings.ContainsRune("{}[]()<>,;:?|&=.", r):
+			t.kind, t.text = tokPunct, string(r)
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at offset %d", r, i)
+		}
+		toks = append(toks, t)
+	}
+	return append(toks, tsToken{kind: tokEOF, pos: len(rs)}), nil
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_' || r == '$'
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
+
+// isIdent determines if the passed string is a valid typescript identifier.
+func isIdent(s string) bool {
+	for i, r := range s {
+		if (i == 0 && !isIdentStart(r)) || !isIdentPart(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// reservedWords are javascript reserved words (including strict mode), that
+// are valid property names but cannot be used as binding names e.g. params.
+var reservedWords = map[string]bool{
+	"await": true, "break": true, "case": true, "catch": true, "class": true,
+	"const": true, "continue": true, "debugger": true, "default": true,
+	"delete": true, "do": true, "else": true, "enum": true, "export": true,
+	"extends": true, "false": true, "finally": true, "for": true,
+	"function": true, "if": true, "implements": true, "import": true,
+	"in": true, "instanceof": true, "interface": true, "let": true,
+	"new": true, "null": true, "package": true, "private": true,
+	"protected": true, "public": true, "return": true, "static": true,
+	"super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true,
+	"with": true, "yield": true,
+}
+
+// tsParser is a recursive descent parser for the subset of typescript type
+// syntax output by the converter.
+type tsParser struct {
+	toks []tsToken
+	i    int
+}
+
+func (p *tsParser) peek() tsToken {
+	return p.peekAt(0)
+}
+
+func (p *tsParser) peekAt(n int) tsToken {
+	if p.i+n >= len(p.toks) {
+		return p.toks[len(p.toks)-1]
+	}
+	return p.toks[p.i+n]
+}
+
+func (p *tsParser) next() tsToken {
+	t := p.peek()
+	if p.i < len(p.toks)-1 {
+		p.i++
+	}
+	return t
+}
+
+// is determines if the next token is punctuation or an identifier with the
+// passed text.
+func (p *tsParser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tokPunct || t.kind == tokIdent) && t.text == text
+}
+
+func (p *tsParser) expect(text string) error {
+	if !p.is(text) {
+		t := p.peek()
+		if t.kind == tokEOF {
+			return fmt.Errorf("expected %q but got end of input", text)
+		}
+		return fmt.Errorf("expected %q but got %q at offset %d", text, t.text, t.pos)
+	}
+	p.next()
+	return nil
+}
+
+// parseType parses a union of intersections.
+func (p *tsParser) parseType() error {
+	for {
+		for {
+			if err := p.parsePostfix(); err != nil {
+				return err
+			}
+			if !p.is("&") {
+				break
+			}
+			p.next()
+		}
+		if !p.is("|") {
+			return nil
+		}
+		p.next()
+	}
+}
+
+// parsePostfix parses a primary type followed by any array shorthand.
+func (p *tsParser) parsePostfix() error {
+	if err := p.parsePrimary(); err != nil {
+		return err
+	}
+	for p.is("[") && p.peekAt(1).text == "]" {
+		p.next()
+		p.next()
+	}
+	return nil
+}
+
+func (p *tsParser) parsePrimary() error {
+	t := p.peek()
+	switch t.kind {
+	case tokString, tokNumber:
+		p.next()
+		return nil
+	case tokIdent:
+		p.next()
+		switch t.text {
+		case "keyof", "typeof", "readonly":
+			return p.parsePostfix()
+		}
+		for p.is(".") {
+			p.next()
+			if p.peek().kind != tokIdent {
+				return p.peek().unexpected()
+			}
+			p.next()
+		}
+		if p.is("<") {
+			p.next()
+			if err := p.parseList(">", p.parseType); err != nil {
+				return err
+			}
+		}
+		return nil
+	case tokPunct:
+		switch t.text {
+		case "{":
+			return p.parseObject()
+		case "[":
+			p.next()
+			return p.parseList("]", p.parseType)
+		case "(":
+			if p.isParams() {
+				if err := p.parseParams(); err != nil {
+					return err
+				}
+				if err := p.expect("=>"); err != nil {
+					return err
+				}
+				return p.parseType()
+			}
+			p.next()
+			if err := p.parseType(); err != nil {
+				return err
+			}
+			return p.expect(")")
+		}
+	}
+	return t.unexpected()
+}
+
+// parseList parses comma separated items up to and including the end token.
+func (p *tsParser) parseList(end string, item func() error) error {
+	for !p.is(end) {
+		if err := item(); err != nil {
+			return err
+		}
+		if !p.is(",") {
+			break
+		}
+		p.next()
+	}
+	return p.expect(end)
+}
+
+// isParams determines if the next tokens start a parameter list rather than
+// a parenthesized type.
+func (p *tsParser) isParams() bool {
+	next := p.peekAt(1)
+	if next.text == ")" || next.text == "..." {
+		return true
+	}
+	after := p.peekAt(2).text
+	if next.text == "{" {
+		// a destructuring pattern e.g. ({ name, age }: T)
+		end := p.peekAt(3).text
+		return p.peekAt(2).kind == tokIdent && (end == "," || end == "}")
+	}
+	return next.kind == tokIdent && (after == ":" || after == "?")
+}
+
+func (p *tsParser) parseParams() error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	names := make(map[string]bool)
+	return p.parseList(")", func() error {
+		if p.is("...") {
+			p.next()
+		}
+		var params []tsToken
+		if p.is("{") {
+			p.next()
+			err := p.parseList("}", func() error {
+				params = append(params, p.next())
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			params = append(params, p.next())
+		}
+		for _, t := range params {
+			if t.kind != tokIdent {
+				return t.unexpected()
+			}
+			if reservedWords[t.text] {
+				return fmt.Errorf("reserved word %q used as parameter name at offset %d", t.text, t.pos)
+			}
+			if names[t.text] {
+				return fmt.Errorf("duplicate parameter %q at offset %d", t.text, t.pos)
+			}
+			names[t.text] = true
+		}
+		if p.is("?") {
+			p.next()
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		return p.parseType()
+	})
+}
+
+// parseSignature parses a parameter list and return type for a method or call
+// signature.
+func (p *tsParser) parseSignature() error {
+	if err := p.parseParams(); err != nil {
+		return err
+	}
+	if err := p.expect(":"); err != nil {
+		return err
+	}
+	return p.parseType()
+}
+
+// parseObject parses an object type literal.
+func (p *tsParser) parseObject() error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	keys := make(map[string]bool)
+	for !p.is("}") {
+		if err := p.parseMember(keys); err != nil {
+			return err
+		}
+		if p.is(",") || p.is(";") {
+			p.next()
+		} else if !p.is("}") && !p.peek().nl {
+			return p.peek().unexpected()
+		}
+	}
+	return p.expect("}")
+}
+
+func (p *tsParser) parseMember(keys map[string]bool) error {
+	if p.is("readonly") {
+		if next := p.peekAt(1).text; next != ":" && next != "?" && next != "(" {
+			p.next()
+		}
+	}
+	if p.is("(") {
+		return p.parseSignature()
+	}
+	if p.is("[") {
+		p.next()
+		if t := p.next(); t.kind != tokIdent {
+			return t.unexpected()
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		if err := p.parseType(); err != nil {
+			return err
+		}
+		if err := p.expect("]"); err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		return p.parseType()
+	}
+
+	t := p.next()
+	key := t.text
+	switch t.kind {
+	case tokString:
+		key = key[1 : len(key)-1]
+	case tokIdent, tokNumber:
+	case tokEOF:
+		return fmt.Errorf("expected \"}\" but got end of input")
+	default:
+		return fmt.Errorf("invalid property name %q at offset %d", t.text, t.pos)
+	}
+	if keys[key] {
+		return fmt.Errorf("duplicate property %q at offset %d", key, t.pos)
+	}
+	keys[key] = true
+
+	if p.is("?") {
+		p.next()
+	}
+	if p.is("(") {
+		return p.parseSignature()
+	}
+	if err := p.expect(":"); err != nil {
+		return err
+	}
+	return p.parseType()
+}
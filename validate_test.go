@@ -0,0 +1,55 @@
+package go2ts
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	c := NewConverter()
+	valid := []string{
+		c.Convert(typ("")),
+		c.Convert(typ(Nested{})),
+		c.Convert(typ(map[string][]*User{})),
+		c.Convert(typ(func(context.Context, string, int) (User, error) { return User{}, nil })),
+		c.Convert(typ(func() chan string { return nil })),
+		"Method (str: string): Promise<void>",
+		"{ \"foo-bar\"?: string, [k: string]: unknown }",
+		"string | { id: string }",
+		"boolean & { __brand: \"Flag\" }",
+		"Array<string | undefined>",
+		"(fn: () => void) => Promise<[string, number]>",
+		"{\n  /** The name */\n  Name: string\n  Age: number\n}",
+		"({ name, age }: { name: string, age: number }) => void",
+		"(x: ({ a: string })) => void",
+		"{ default: string, class: number }",
+	}
+	for _, ts := range valid {
+		if err := Validate(ts); err != nil {
+			t.Fatalf("expected \"%s\" to be valid: %v", ts, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"{ Name: string",
+		"Array<string",
+		"{ Name: string }}",
+		"{ foo-bar: string }",
+		"{ Name: string, Name: number }",
+		"string | ",
+		"string || number",
+		"| string",
+		"(str: string, str: number) => void",
+		"({ name, name }: { name: string }) => void",
+		"({ default, class }: { default: string, class: number }) => void",
+		"(function: () => void) => void",
+		"{ \"Name: string }",
+		"Array<string>>",
+	}
+	for _, ts := range invalid {
+		if err := Validate(ts); err == nil {
+			t.Fatalf("expected \"%s\" to be invalid", ts)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package go2ts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConvertZod takes a golang reflect.Type and returns a Zod schema expression
+// e.g. z.object({ Name: z.string() }). Struct fields are named, omitted and
+// made optional in the same way as Converter.Convert and pointers are nullable
+// in PointerModeNull.
+//
+// Custom types added with Converter.AddTypes are NOT used since they are
+// typescript types, and functions, channels and recursive types are NOT
+// supported.
+func (c *Converter) ConvertZod(t reflect.Type) string {
+	return c.zod(t, make(map[reflect.Type]bool))
+}
+
+func (c *Converter) zod(t reflect.Type, visiting map[reflect.Type]bool) string {
+	if members, ok := c.enums[t]; ok {
+		return zodEnum(members)
+	}
+
+	kind := t.Kind()
+	switch {
+	case t == timeType:
+		return "z.string()"
+	case t == jsonNumberType:
+		return "z.union([z.number(), z.string()])"
+	case isMarshaler(t):
+		return "z.unknown()"
+	case kind == reflect.Bool:
+		return "z.boolean()"
+	case kind == reflect.String:
+		return "z.string()"
+	case isInt(kind) || kind == reflect.Uintptr || kind == reflect.Float32 || kind == reflect.Float64:
+		return "z.number()"
+	case kind == reflect.Interface:
+		return "z.any()"
+	case kind == reflect.Ptr:
+		z := c.zod(t.Elem(), visiting)
+		if c.PointerMode == PointerModeNull && !strings.HasSuffix(z, ".nullable()") {
+			z += ".nullable()"
+		}
+		return z
+	case kind == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return "z.string()"
+	case kind == reflect.Slice || kind == reflect.Array:
+		return fmt.Sprintf("z.array(%s)", c.zod(t.Elem(), visiting))
+	case kind == reflect.Map:
+		return fmt.Sprintf("z.record(z.string(), %s)", c.zod(t.Elem(), visiting))
+	case kind == reflect.Struct:
+		if visiting[t] {
+			panic(convertErrorf("unhandled recursive type: %v", t))
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		var props []string
+		var catchall string
+		for _, f := range c.structFields(t) {
+			if f.Inline {
+				catchall = fmt.Sprintf(".catchall(%s)", c.zod(f.GoType.Elem(), visiting))
+				continue
+			}
+			z := "z.string()"
+			if !f.Quoted {
+				z = c.zod(f.GoType, visiting)
+			}
+			if f.Optional {
+				z += ".optional()"
+			}
+			props = append(props, fmt.Sprintf("%s: %s", propName(f.Name), z))
+		}
+		if len(props) == 0 {
+			return "z.object({})" + catchall
+		}
+		return fmt.Sprintf("z.object({ %s })%s", strings.Join(props, ", "), catchall)
+	}
+	panic(convertErrorf("unhandled type: %v (%s)", t, t.Kind()))
+}
+
+// zodEnum converts enum members to a Zod schema expression.
+func zodEnum(members []Const) string {
+	var lits []string
+	strs := true
+	for _, m := range members {
+		_, ok := m.Value.(string)
+		strs = strs && ok
+		lits = append(lits, literal(m.Value))
+	}
+	if strs {
+		return fmt.Sprintf("z.enum([%s])", strings.Join(lits, ", "))
+	}
+	for i, l := range lits {
+		lits[i] = fmt.Sprintf("z.literal(%s)", l)
+	}
+	return fmt.Sprintf("z.union([%s])", strings.Join(lits, ", "))
+}
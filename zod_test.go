@@ -0,0 +1,49 @@
+package go2ts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConvertZod(t *testing.T) {
+	type Profile struct {
+		Name    string            `json:"name"`
+		Email   string            `json:"email,omitempty"`
+		Age     int               `json:"age,string"`
+		Tags    []string          `json:"tags"`
+		Meta    map[string]int    `json:"meta"`
+		Owner   *User             `json:"owner"`
+		Created time.Time         `json:"created"`
+		Avatar  []byte            `json:"avatar"`
+		Extra   interface{}       `json:"extra"`
+		Secret  string            `json:"-"`
+		Nested  map[string][]User `json:"nested"`
+	}
+	c := NewConverter()
+	expect(t, c.ConvertZod(typ("")), "z.string()")
+	expect(t, c.ConvertZod(typ(0)), "z.number()")
+	expect(t, c.ConvertZod(typ(true)), "z.boolean()")
+	expect(t, c.ConvertZod(typ([]int{})), "z.array(z.number())")
+	expect(t, c.ConvertZod(typ(map[string]bool{})), "z.record(z.string(), z.boolean())")
+	expect(t, c.ConvertZod(typ(struct{}{})), "z.object({})")
+	expect(t, c.ConvertZod(typ(Profile{})), "z.object({ name: z.string(), email: z.string().optional(), age: z.string(), tags: z.array(z.string()), meta: z.record(z.string(), z.number()), owner: z.object({ Name: z.string() }), created: z.string(), avatar: z.string(), extra: z.any(), nested: z.record(z.string(), z.array(z.object({ Name: z.string() }))) })")
+
+	c.PointerMode = PointerModeNull
+	expect(t, c.ConvertZod(typ(struct{ Owner *User }{})), "z.object({ Owner: z.object({ Name: z.string() }).nullable() })")
+
+	c.AddEnum(typ(State(0)), []Const{{"Pending", 0}, {"Active", 1}})
+	c.AddEnum(typ(Color("")), []Const{{"Red", "red"}, {"Green", "green"}})
+	expect(t, c.ConvertZod(typ(State(0))), "z.union([z.literal(0), z.literal(1)])")
+	expect(t, c.ConvertZod(typ(Color(""))), "z.enum([\"red\", \"green\"])")
+}
+
+type Node struct{ Next *Node }
+
+func TestConvertZodRecursive(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic converting recursive type")
+		}
+	}()
+	NewConverter().ConvertZod(typ(Node{}))
+}